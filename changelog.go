@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/coze-dev/coze-sdk-gen/diff"
+	"github.com/spf13/cobra"
+)
+
+var (
+	currentVersion string
+	changelogPath  string
+	releaseDate    string
+)
+
+func init() {
+	changelogCmd.Flags().StringVar(&currentVersion, "current-version", "", "Version of the SDK generated from the old spec, e.g. 0.3.1")
+	changelogCmd.Flags().StringVar(&changelogPath, "file", "CHANGELOG.md", "Changelog file the entry is prepended to, - prints the entry instead")
+	changelogCmd.Flags().StringVar(&releaseDate, "date", time.Now().Format("2006-01-02"), "Release date of the entry")
+	changelogCmd.Flags().StringArrayVar(&overlays, "overlay", nil, "OpenAPI Overlay applied to both specs before parsing, can be repeated")
+	changelogCmd.MarkFlagRequired("current-version")
+	rootCmd.AddCommand(changelogCmd)
+}
+
+var changelogCmd = &cobra.Command{
+	Use:   "changelog <old.yaml|dir> <new.yaml|dir>",
+	Short: "Write a changelog entry and suggest the next SDK version",
+	Long: `Compares two spec versions and prepends an entry to the changelog, grouped by module into
+breaking changes, added operations, new fields, other changes and deprecations. The version of the entry is
+the suggested next semantic version of the SDK.`,
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		report, err := compareSpecs(args[0], args[1], overlays)
+		if err != nil {
+			return err
+		}
+
+		version, err := report.SuggestVersion(currentVersion)
+		if err != nil {
+			return err
+		}
+		entry := report.Changelog(version, releaseDate)
+
+		if changelogPath == "-" {
+			fmt.Print(entry)
+		} else {
+			existing, err := os.ReadFile(changelogPath)
+			if err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to read changelog: %v", err)
+			}
+			if err := os.WriteFile(changelogPath, []byte(diff.PrependChangelog(string(existing), entry)), 0644); err != nil {
+				return fmt.Errorf("failed to write changelog: %v", err)
+			}
+		}
+
+		// The entry may be piped to a file, the suggestion is not part of it
+		fmt.Fprintf(os.Stderr, "Suggested version: %s (%s bump)\n", version, report.Bump())
+		return nil
+	},
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/coze-dev/coze-sdk-gen/consts"
+	"github.com/coze-dev/coze-sdk-gen/diff"
+	"github.com/coze-dev/coze-sdk-gen/generator"
+	"github.com/coze-dev/coze-sdk-gen/parser"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	diffCmd.Flags().StringArrayVar(&overlays, "overlay", nil, "OpenAPI Overlay applied to both specs before parsing, can be repeated")
+	rootCmd.AddCommand(diffCmd)
+}
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <old.yaml|dir> <new.yaml|dir>",
+	Short: "Report the changes between two spec versions",
+	Long: `Parses both specs as the Python SDK is generated from them and prints the changes of each module as a Markdown changelog section.
+Exits with a non-zero status when a change breaks users of the generated SDK.`,
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		report, err := compareSpecs(args[0], args[1], overlays)
+		if err != nil {
+			return err
+		}
+
+		fmt.Print(report.Markdown("API changes"))
+		if report.HasBreaking() {
+			return fmt.Errorf("breaking changes found")
+		}
+		return nil
+	},
+}
+
+// compareSpecs parses two spec versions, patched with the same overlays, and compares their modules
+func compareSpecs(oldPath, newPath string, overlays []string) (*diff.Report, error) {
+	oldModules, err := parseModules(oldPath, overlays)
+	if err != nil {
+		return nil, err
+	}
+	newModules, err := parseModules(newPath, overlays)
+	if err != nil {
+		return nil, err
+	}
+	return diff.Compare(oldModules, newModules), nil
+}
+
+// parseModules parses the specs of a file or directory into modules, with the config the
+// Python SDK is generated with, so changes are judged on the names its users call
+func parseModules(path string, overlays []string) (map[string]*parser.Module, error) {
+	specs, err := readSpecs([]string{path})
+	if err != nil {
+		return nil, err
+	}
+	if specs, err = applyOverlays(specs, overlays); err != nil {
+		return nil, err
+	}
+	p, err := generator.NewParser(consts.Python)
+	if err != nil {
+		return nil, err
+	}
+	modules, err := p.ParseOpenAPISpecs(specs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return modules, nil
+}
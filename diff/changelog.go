@@ -0,0 +1,140 @@
+package diff
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Bump represents the semantic version increment a report calls for
+type Bump string
+
+const (
+	BumpNone  Bump = "none"
+	BumpPatch Bump = "patch"
+	BumpMinor Bump = "minor"
+	BumpMajor Bump = "major"
+)
+
+// changelogGroups are the sections of a module in a changelog entry, in order
+var changelogGroups = []struct {
+	title   string
+	matches func(Change) bool
+}{
+	{"Breaking changes", func(c Change) bool { return c.Breaking }},
+	{"Added operations", func(c Change) bool {
+		return !c.Breaking && (c.Kind == ChangeAddedHandler || c.Kind == ChangeAddedModule)
+	}},
+	{"New fields", func(c Change) bool {
+		return !c.Breaking && (c.Kind == ChangeAddedField || c.Kind == ChangeAddedType || c.Kind == ChangeAddedEnumValue)
+	}},
+	{"Changed", func(c Change) bool { return !c.Breaking && c.Kind == ChangeOptionalField }},
+	{"Deprecations", func(c Change) bool { return !c.Breaking && c.Kind == ChangeDeprecated }},
+}
+
+// Bump returns the version increment of the report: major for breaking changes, minor for
+// additions and deprecations and patch for anything else
+func (r *Report) Bump() Bump {
+	bump := BumpNone
+	for _, change := range r.Changes {
+		switch {
+		case change.Breaking:
+			return BumpMajor
+		case change.Kind == ChangeAddedHandler || change.Kind == ChangeAddedModule || change.Kind == ChangeAddedField ||
+			change.Kind == ChangeAddedType || change.Kind == ChangeAddedEnumValue || change.Kind == ChangeDeprecated:
+			bump = BumpMinor
+		case bump == BumpNone:
+			bump = BumpPatch
+		}
+	}
+	return bump
+}
+
+// SuggestVersion returns the next version of an SDK released at current, e.g. 1.2.3.
+// Before 1.0.0 breaking changes only bump the minor version.
+func (r *Report) SuggestVersion(current string) (string, error) {
+	prefix := ""
+	if strings.HasPrefix(current, "v") {
+		prefix, current = "v", current[1:]
+	}
+	// Drop pre-release and build metadata
+	core, _, _ := strings.Cut(current, "-")
+	core, _, _ = strings.Cut(core, "+")
+
+	parts := strings.Split(core, ".")
+	if len(parts) != 3 {
+		return "", fmt.Errorf("invalid version %q, expected MAJOR.MINOR.PATCH", current)
+	}
+	numbers := make([]int, 3)
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return "", fmt.Errorf("invalid version %q, expected MAJOR.MINOR.PATCH", current)
+		}
+		numbers[i] = n
+	}
+	major, minor, patch := numbers[0], numbers[1], numbers[2]
+
+	switch r.Bump() {
+	case BumpMajor:
+		if major == 0 {
+			minor, patch = minor+1, 0
+		} else {
+			major, minor, patch = major+1, 0, 0
+		}
+	case BumpMinor:
+		minor, patch = minor+1, 0
+	case BumpPatch:
+		patch++
+	}
+	return fmt.Sprintf("%s%d.%d.%d", prefix, major, minor, patch), nil
+}
+
+// Changelog renders the report as a CHANGELOG.md entry for a version, grouped by module
+func (r *Report) Changelog(version, date string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "## %s", version)
+	if date != "" {
+		fmt.Fprintf(&sb, " (%s)", date)
+	}
+	sb.WriteString("\n")
+	if len(r.Changes) == 0 {
+		sb.WriteString("\nNo API changes.\n")
+		return sb.String()
+	}
+
+	for _, module := range r.Modules() {
+		var sections []string
+		for _, group := range changelogGroups {
+			var lines []string
+			for _, change := range r.Changes {
+				if change.Module == module && group.matches(change) {
+					lines = append(lines, "- "+change.Message)
+				}
+			}
+			if len(lines) > 0 {
+				sections = append(sections, fmt.Sprintf("#### %s\n\n%s\n", group.title, strings.Join(lines, "\n")))
+			}
+		}
+		if len(sections) > 0 {
+			fmt.Fprintf(&sb, "\n### %s\n\n%s", module, strings.Join(sections, "\n"))
+		}
+	}
+	return sb.String()
+}
+
+// PrependChangelog inserts an entry at the top of a changelog, below its title
+func PrependChangelog(changelog, entry string) string {
+	const title = "# Changelog\n"
+	if !strings.HasPrefix(changelog, title) {
+		if strings.TrimSpace(changelog) == "" {
+			return title + "\n" + entry
+		}
+		return title + "\n" + entry + "\n" + changelog
+	}
+	rest := strings.TrimLeft(strings.TrimPrefix(changelog, title), "\n")
+	if rest == "" {
+		return title + "\n" + entry
+	}
+	return title + "\n" + entry + "\n" + rest
+}
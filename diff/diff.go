@@ -0,0 +1,423 @@
+package diff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/coze-dev/coze-sdk-gen/parser"
+)
+
+// ChangeKind represents the kind of a change between two versions of the IR
+type ChangeKind string
+
+const (
+	ChangeAddedModule       ChangeKind = "added-module"
+	ChangeRemovedModule     ChangeKind = "removed-module"
+	ChangeAddedHandler      ChangeKind = "added-handler"
+	ChangeRemovedHandler    ChangeKind = "removed-handler"
+	ChangeRenamedHandler    ChangeKind = "renamed-handler"
+	ChangeAddedType         ChangeKind = "added-type"
+	ChangeRemovedType       ChangeKind = "removed-type"
+	ChangeAddedField        ChangeKind = "added-field"
+	ChangeRemovedField      ChangeKind = "removed-field"
+	ChangeRequiredField     ChangeKind = "required-field" // optional to required, or a new required field
+	ChangeOptionalField     ChangeKind = "optional-field" // required to optional
+	ChangeTypeChanged       ChangeKind = "type-changed"
+	ChangeAddedEnumValue    ChangeKind = "added-enum-value"
+	ChangeRemovedEnumValue  ChangeKind = "removed-enum-value"
+	ChangePaginationChanged ChangeKind = "pagination-changed"
+	ChangeDeprecated        ChangeKind = "deprecated"
+)
+
+// Change is a single difference between two versions of the IR
+type Change struct {
+	Module   string     `json:"module"`
+	Kind     ChangeKind `json:"kind"`
+	Subject  string     `json:"subject"` // handler, type or field path, e.g. Bot.name
+	Message  string     `json:"message"`
+	Breaking bool       `json:"breaking"`
+}
+
+// Report lists the changes between two versions of the IR
+type Report struct {
+	Changes []Change `json:"changes"`
+}
+
+// HasBreaking checks if any change breaks users of the generated SDK
+func (r *Report) HasBreaking() bool {
+	for _, change := range r.Changes {
+		if change.Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+// Modules returns the names of the modules with changes, sorted
+func (r *Report) Modules() []string {
+	seen := make(map[string]bool)
+	var modules []string
+	for _, change := range r.Changes {
+		if !seen[change.Module] {
+			seen[change.Module] = true
+			modules = append(modules, change.Module)
+		}
+	}
+	sort.Strings(modules)
+	return modules
+}
+
+// differ compares two versions of the IR
+type differ struct {
+	report      *Report
+	comparedTys map[string]bool
+}
+
+// Compare classifies the changes between the modules of an old and a new spec
+func Compare(oldModules, newModules map[string]*parser.Module) *Report {
+	d := &differ{report: &Report{}, comparedTys: make(map[string]bool)}
+
+	for _, name := range sortedKeys(oldModules, newModules) {
+		switch {
+		case oldModules[name] == nil:
+			d.add(name, ChangeAddedModule, name, false, "module %s was added", name)
+		case newModules[name] == nil:
+			d.add(name, ChangeRemovedModule, name, true, "module %s was removed", name)
+		}
+	}
+	d.compareHandlers(handlersByName(oldModules), handlersByName(newModules))
+
+	// Named types are compared once, under the module they are generated in
+	oldTypes, newTypes := namedTypes(oldModules), namedTypes(newModules)
+	for _, name := range sortedKeys(oldTypes, newTypes) {
+		oldTy, newTy := oldTypes[name], newTypes[name]
+		switch {
+		case oldTy == nil:
+			d.add(newTy.Module, ChangeAddedType, name, false, "type %s was added", name)
+		case newTy == nil:
+			d.add(oldTy.Module, ChangeRemovedType, name, true, "type %s was removed", name)
+		default:
+			d.compareNamedType(newTy.Module, oldTy, newTy)
+		}
+	}
+
+	sort.SliceStable(d.report.Changes, func(i, j int) bool {
+		return d.report.Changes[i].Module < d.report.Changes[j].Module
+	})
+	return d.report
+}
+
+func (d *differ) add(module string, kind ChangeKind, subject string, breaking bool, format string, args ...any) {
+	d.report.Changes = append(d.report.Changes, Change{
+		Module:   module,
+		Kind:     kind,
+		Subject:  subject,
+		Message:  fmt.Sprintf(format, args...),
+		Breaking: breaking,
+	})
+}
+
+// compareHandlers compares handlers by name. A removed handler whose route is taken by an added
+// handler is reported as renamed.
+func (d *differ) compareHandlers(oldHandlers, newHandlers map[string]moduleHandler) {
+	newByRoute := make(map[string]string)
+	for name, handler := range newHandlers {
+		if _, ok := oldHandlers[name]; !ok {
+			newByRoute[route(handler.handler)] = name
+		}
+	}
+
+	// Resolve renames first, so that the new name is not reported as added
+	renames := make(map[string]string)
+	renamed := make(map[string]bool)
+	for name, handler := range oldHandlers {
+		if _, ok := newHandlers[name]; ok {
+			continue
+		}
+		if newName, ok := newByRoute[route(handler.handler)]; ok {
+			renames[name] = newName
+			renamed[newName] = true
+		}
+	}
+
+	for _, name := range sortedKeys(oldHandlers, newHandlers) {
+		oldHandler, oldOk := oldHandlers[name]
+		newHandler, newOk := newHandlers[name]
+		switch {
+		case !oldOk:
+			if !renamed[name] {
+				d.add(newHandler.module, ChangeAddedHandler, name, false, "operation %s (%s) was added", name, route(newHandler.handler))
+			}
+		case !newOk:
+			if newName, ok := renames[name]; ok {
+				d.add(oldHandler.module, ChangeRenamedHandler, name, true, "operation %s was renamed to %s", name, newName)
+				d.compareHandler(oldHandler.module, oldHandler.handler, newHandlers[newName].handler)
+			} else {
+				d.add(oldHandler.module, ChangeRemovedHandler, name, true, "operation %s (%s) was removed", name, route(oldHandler.handler))
+			}
+		default:
+			if oldHandler.module != newHandler.module {
+				d.add(newHandler.module, ChangeRenamedHandler, name, true, "operation %s moved from module %s to %s", name, oldHandler.module, newHandler.module)
+			}
+			d.compareHandler(newHandler.module, oldHandler.handler, newHandler.handler)
+		}
+	}
+}
+
+// compareHandler compares the parameters, bodies and pagination of a handler
+func (d *differ) compareHandler(module string, oldHandler, newHandler *parser.HttpHandler) {
+	name := oldHandler.Name
+	d.compareDeprecation(module, name, "operation", oldHandler.Deprecation, newHandler.Deprecation)
+	d.compareInputs(module, name, inputs(oldHandler), inputs(newHandler))
+
+	if oldHandler.ResponseKind != newHandler.ResponseKind {
+		d.add(module, ChangeTypeChanged, name, true, "response of %s changed from %s to %s", name, responseKind(oldHandler), responseKind(newHandler))
+	} else {
+		d.compareType(module, name+" response", oldHandler.ResponseBody, newHandler.ResponseBody)
+	}
+
+	oldPage, newPage := oldHandler.GetPageInfo(nil, nil), newHandler.GetPageInfo(nil, nil)
+	switch {
+	case oldPage == nil && newPage != nil:
+		d.add(module, ChangePaginationChanged, name, true, "%s is now paginated by %s and %s", name, newPage.PageIndexName, newPage.PageSizeName)
+	case oldPage != nil && newPage == nil:
+		d.add(module, ChangePaginationChanged, name, true, "%s is no longer paginated", name)
+	case oldPage != nil && (oldPage.PageIndexName != newPage.PageIndexName || oldPage.PageSizeName != newPage.PageSizeName):
+		d.add(module, ChangePaginationChanged, name, true, "pagination of %s changed from %s/%s to %s/%s", name,
+			oldPage.PageIndexName, oldPage.PageSizeName, newPage.PageIndexName, newPage.PageSizeName)
+	case oldPage != nil && typeName(oldPage.ItemType) != typeName(newPage.ItemType):
+		d.add(module, ChangePaginationChanged, name, true, "items of %s changed from %s to %s", name, typeName(oldPage.ItemType), typeName(newPage.ItemType))
+	}
+}
+
+// compareInputs compares the parameters of a handler, new required inputs break callers
+func (d *differ) compareInputs(module, handler string, oldInputs, newInputs map[string]parser.TyField) {
+	for _, name := range sortedKeys(oldInputs, newInputs) {
+		oldField, oldOk := oldInputs[name]
+		newField, newOk := newInputs[name]
+		subject := handler + "." + name
+		switch {
+		case !oldOk && newField.Required:
+			d.add(module, ChangeRequiredField, subject, true, "required parameter %s was added to %s", name, handler)
+		case !oldOk:
+			d.add(module, ChangeAddedField, subject, false, "parameter %s was added to %s", name, handler)
+		case !newOk:
+			d.add(module, ChangeRemovedField, subject, true, "parameter %s was removed from %s", name, handler)
+		default:
+			d.compareField(module, subject, "parameter", &oldField, &newField)
+		}
+	}
+}
+
+// compareNamedType compares two versions of a named type
+func (d *differ) compareNamedType(module string, oldTy, newTy *parser.Ty) {
+	if d.comparedTys[oldTy.Name] {
+		return
+	}
+	d.comparedTys[oldTy.Name] = true
+
+	if oldTy.Kind != newTy.Kind {
+		d.add(module, ChangeTypeChanged, oldTy.Name, true, "type %s changed from %s to %s", oldTy.Name, oldTy.Kind, newTy.Kind)
+		return
+	}
+	d.compareDeprecation(module, oldTy.Name, "type", oldTy.Deprecation, newTy.Deprecation)
+	d.compareEnum(module, oldTy.Name, oldTy, newTy)
+	d.compareFields(module, oldTy.Name, oldTy, newTy)
+}
+
+// compareDeprecation reports an operation, type or field that became deprecated
+func (d *differ) compareDeprecation(module, subject, what string, oldDeprecation, newDeprecation parser.Deprecation) {
+	if oldDeprecation.Deprecated || !newDeprecation.Deprecated {
+		return
+	}
+	message := fmt.Sprintf("%s %s was deprecated", what, subject)
+	if newDeprecation.DeprecatedReplacement != "" {
+		message += ", use " + newDeprecation.DeprecatedReplacement + " instead"
+	}
+	d.add(module, ChangeDeprecated, subject, false, "%s", message)
+}
+
+// compareType compares the types at the same position, named types are compared on their own
+func (d *differ) compareType(module, subject string, oldTy, newTy *parser.Ty) {
+	if oldTy == nil || newTy == nil {
+		if (oldTy == nil) != (newTy == nil) {
+			d.add(module, ChangeTypeChanged, subject, true, "%s changed from %s to %s", subject, typeName(oldTy), typeName(newTy))
+		}
+		return
+	}
+	if typeName(oldTy) != typeName(newTy) {
+		d.add(module, ChangeTypeChanged, subject, true, "%s changed from %s to %s", subject, typeName(oldTy), typeName(newTy))
+		return
+	}
+
+	switch {
+	case oldTy.IsNamed:
+		return
+	case oldTy.Kind == parser.TyKindArray:
+		d.compareType(module, subject+"[]", oldTy.ElementType, newTy.ElementType)
+	case oldTy.Kind == parser.TyKindMap:
+		d.compareType(module, subject+"{}", oldTy.ValueType, newTy.ValueType)
+	default:
+		d.compareEnum(module, subject, oldTy, newTy)
+		d.compareFields(module, subject, oldTy, newTy)
+	}
+}
+
+// compareFields compares the fields of two object types
+func (d *differ) compareFields(module, subject string, oldTy, newTy *parser.Ty) {
+	oldFields, newFields := fieldsByName(oldTy.Fields), fieldsByName(newTy.Fields)
+	for _, name := range sortedKeys(oldFields, newFields) {
+		oldField, oldOk := oldFields[name]
+		newField, newOk := newFields[name]
+		fieldSubject := subject + "." + name
+		switch {
+		case !oldOk && newField.Required:
+			d.add(module, ChangeRequiredField, fieldSubject, true, "required field %s was added", fieldSubject)
+		case !oldOk:
+			d.add(module, ChangeAddedField, fieldSubject, false, "field %s was added", fieldSubject)
+		case !newOk:
+			d.add(module, ChangeRemovedField, fieldSubject, true, "field %s was removed", fieldSubject)
+		default:
+			d.compareField(module, fieldSubject, "field", &oldField, &newField)
+		}
+	}
+}
+
+// compareField compares two versions of a field or parameter
+func (d *differ) compareField(module, subject, what string, oldField, newField *parser.TyField) {
+	switch {
+	case !oldField.Required && newField.Required:
+		d.add(module, ChangeRequiredField, subject, true, "%s %s changed from optional to required", what, subject)
+	case oldField.Required && !newField.Required:
+		d.add(module, ChangeOptionalField, subject, false, "%s %s changed from required to optional", what, subject)
+	}
+	d.compareDeprecation(module, subject, what, oldField.Deprecation, newField.Deprecation)
+	d.compareType(module, subject, oldField.Type, newField.Type)
+}
+
+// compareEnum compares the values of two enums
+func (d *differ) compareEnum(module, subject string, oldTy, newTy *parser.Ty) {
+	oldValues, newValues := enumValues(oldTy), enumValues(newTy)
+	for _, value := range sortedKeys(oldValues, newValues) {
+		switch {
+		case !oldValues[value]:
+			d.add(module, ChangeAddedEnumValue, subject, false, "enum value %s was added to %s", value, subject)
+		case !newValues[value]:
+			d.add(module, ChangeRemovedEnumValue, subject, true, "enum value %s was removed from %s", value, subject)
+		}
+	}
+}
+
+// inputs returns the parameters of a handler with the fields of its request body
+func inputs(handler *parser.HttpHandler) map[string]parser.TyField {
+	result := make(map[string]parser.TyField)
+	for _, params := range [][]parser.TyField{handler.PathParams, handler.QueryParams, handler.HeaderParams, handler.CookieParams} {
+		for _, param := range params {
+			result[param.Name] = param
+		}
+	}
+	if handler.RequestBody != nil {
+		for _, field := range handler.RequestBody.Fields {
+			result[field.Name] = field
+		}
+	}
+	return result
+}
+
+// namedTypes returns the named types of all modules indexed by name
+func namedTypes(modules map[string]*parser.Module) map[string]*parser.Ty {
+	result := make(map[string]*parser.Ty)
+	for _, module := range modules {
+		for _, ty := range module.Types {
+			if ty.IsNamed {
+				result[ty.Name] = ty
+			}
+		}
+	}
+	return result
+}
+
+// moduleHandler is a handler with the module it belongs to
+type moduleHandler struct {
+	module  string
+	handler *parser.HttpHandler
+}
+
+// handlersByName returns the handlers of all modules indexed by name
+func handlersByName(modules map[string]*parser.Module) map[string]moduleHandler {
+	result := make(map[string]moduleHandler)
+	for name, module := range modules {
+		for i := range module.HttpHandlers {
+			result[module.HttpHandlers[i].Name] = moduleHandler{module: name, handler: &module.HttpHandlers[i]}
+		}
+	}
+	return result
+}
+
+func fieldsByName(fields []parser.TyField) map[string]parser.TyField {
+	result := make(map[string]parser.TyField)
+	for _, field := range fields {
+		result[field.Name] = field
+	}
+	return result
+}
+
+func enumValues(ty *parser.Ty) map[string]bool {
+	result := make(map[string]bool)
+	for _, value := range ty.EnumValues {
+		result[fmt.Sprint(value.Val)] = true
+	}
+	return result
+}
+
+func route(handler *parser.HttpHandler) string {
+	return strings.ToUpper(handler.Method) + " " + handler.Path
+}
+
+func responseKind(handler *parser.HttpHandler) parser.ResponseKind {
+	if handler.ResponseKind == "" {
+		return parser.ResponseKindJson
+	}
+	return handler.ResponseKind
+}
+
+// typeName returns a short description of a type, e.g. string, Bot or array of Bot
+func typeName(ty *parser.Ty) string {
+	if ty == nil {
+		return "nothing"
+	}
+	if ty.IsNamed {
+		return ty.Name
+	}
+	switch ty.Kind {
+	case parser.TyKindPrimitive:
+		return string(ty.PrimitiveKind)
+	case parser.TyKindArray:
+		return "array of " + typeName(ty.ElementType)
+	case parser.TyKindMap:
+		return "map of " + typeName(ty.ValueType)
+	default:
+		return string(ty.Kind)
+	}
+}
+
+// sortedKeys returns the keys of both maps, sorted
+func sortedKeys[V any, W any](a map[string]V, b map[string]W) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for key := range a {
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	for key := range b {
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
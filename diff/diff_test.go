@@ -0,0 +1,222 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/coze-dev/coze-sdk-gen/parser"
+	"github.com/stretchr/testify/require"
+)
+
+const oldSpec = `
+openapi: 3.0.3
+info:
+  title: test
+  version: "1"
+paths:
+  /v1/bots:
+    get:
+      operationId: ListBots
+      tags:
+        - bots
+      parameters:
+        - name: space_id
+          in: query
+          schema:
+            type: string
+        - name: page_num
+          in: query
+          schema:
+            type: integer
+        - name: page_size
+          in: query
+          schema:
+            type: integer
+      responses:
+        "200":
+          description: ""
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  data:
+                    type: object
+                    properties:
+                      items:
+                        type: array
+                        items:
+                          $ref: "#/components/schemas/Bot"
+  /v1/bot/get:
+    get:
+      operationId: GetBot
+      tags:
+        - bots
+      responses:
+        "200":
+          description: ""
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Bot"
+components:
+  schemas:
+    Bot:
+      type: object
+      properties:
+        bot_id:
+          type: string
+        name:
+          type: string
+        status:
+          $ref: "#/components/schemas/BotStatus"
+    BotStatus:
+      type: integer
+      enum: [0, 1, 2]
+`
+
+const newSpec = `
+openapi: 3.0.3
+info:
+  title: test
+  version: "1"
+paths:
+  /v1/bots:
+    get:
+      operationId: ListBots
+      deprecated: true
+      tags:
+        - bots
+      parameters:
+        - name: space_id
+          in: query
+          required: true
+          schema:
+            type: string
+        - name: page_num
+          in: query
+          schema:
+            type: integer
+      responses:
+        "200":
+          description: ""
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  data:
+                    type: object
+                    properties:
+                      items:
+                        type: array
+                        items:
+                          $ref: "#/components/schemas/Bot"
+  /v1/bot/get:
+    get:
+      operationId: RetrieveBot
+      tags:
+        - bots
+      responses:
+        "200":
+          description: ""
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Bot"
+components:
+  schemas:
+    Bot:
+      type: object
+      properties:
+        bot_id:
+          type: integer
+        status:
+          $ref: "#/components/schemas/BotStatus"
+        icon_url:
+          type: string
+    BotStatus:
+      type: integer
+      enum: [0, 1, 3]
+`
+
+func parse(t *testing.T, spec string) map[string]*parser.Module {
+	p, err := parser.NewParser(nil)
+	require.NoError(t, err)
+	modules, err := p.ParseOpenAPI([]byte(spec))
+	require.NoError(t, err)
+	return modules
+}
+
+func TestCompare(t *testing.T) {
+	report := Compare(parse(t, oldSpec), parse(t, newSpec))
+	require.True(t, report.HasBreaking())
+
+	changes := make(map[string]Change)
+	for _, change := range report.Changes {
+		require.Equal(t, "bots", change.Module)
+		changes[string(change.Kind)+" "+change.Subject] = change
+	}
+	require.Contains(t, changes, "renamed-handler GetBot")
+	require.NotContains(t, changes, "added-handler RetrieveBot")
+	require.Contains(t, changes, "required-field ListBots.space_id")
+	require.Contains(t, changes, "removed-field ListBots.page_size")
+	require.Contains(t, changes, "pagination-changed ListBots")
+	require.Contains(t, changes, "removed-field Bot.name")
+	require.Contains(t, changes, "type-changed Bot.bot_id")
+	require.Contains(t, changes, "removed-enum-value BotStatus")
+	require.Contains(t, changes, "added-enum-value BotStatus")
+	require.False(t, changes["added-field Bot.icon_url"].Breaking)
+	require.False(t, changes["deprecated ListBots"].Breaking)
+
+	markdown := report.Markdown("API changes")
+	require.Contains(t, markdown, "### bots\n\n**Breaking changes**\n\n- ")
+	require.Contains(t, markdown, "- operation GetBot was renamed to RetrieveBot\n")
+	require.Contains(t, markdown, "**Other changes**\n\n")
+}
+
+func TestCompare_NoChanges(t *testing.T) {
+	report := Compare(parse(t, oldSpec), parse(t, oldSpec))
+	require.Empty(t, report.Changes)
+	require.Equal(t, "## API changes\n\nNo changes.\n", report.Markdown("API changes"))
+}
+
+func TestReport_SuggestVersion(t *testing.T) {
+	breaking := &Report{Changes: []Change{{Kind: ChangeRemovedField, Breaking: true}}}
+	added := &Report{Changes: []Change{{Kind: ChangeAddedField}}}
+	other := &Report{Changes: []Change{{Kind: ChangeOptionalField}}}
+	tests := []struct {
+		report  *Report
+		current string
+		want    string
+	}{
+		{breaking, "1.2.3", "2.0.0"},
+		{breaking, "0.2.3", "0.3.0"},
+		{added, "v1.2.3", "v1.3.0"},
+		{other, "1.2.3-beta.1", "1.2.4"},
+		{&Report{}, "1.2.3", "1.2.3"},
+	}
+	for _, tt := range tests {
+		version, err := tt.report.SuggestVersion(tt.current)
+		require.NoError(t, err)
+		require.Equal(t, tt.want, version)
+	}
+
+	_, err := added.SuggestVersion("1.2")
+	require.Error(t, err)
+}
+
+func TestReport_Changelog(t *testing.T) {
+	report := Compare(parse(t, oldSpec), parse(t, newSpec))
+	entry := report.Changelog("1.0.0", "2024-01-02")
+	require.Contains(t, entry, "## 1.0.0 (2024-01-02)\n\n### bots\n\n#### Breaking changes\n\n- ")
+	require.Contains(t, entry, "#### New fields\n\n- field Bot.icon_url was added\n")
+	require.Contains(t, entry, "#### Deprecations\n\n- operation ListBots was deprecated\n")
+
+	optional := &Report{Changes: []Change{{Module: "bots", Kind: ChangeOptionalField, Message: "field Bot.name changed from required to optional"}}}
+	require.Equal(t, "## 1.0.1\n\n### bots\n\n#### Changed\n\n- field Bot.name changed from required to optional\n", optional.Changelog("1.0.1", ""))
+
+	changelog := PrependChangelog("", entry)
+	require.Equal(t, "# Changelog\n\n"+entry, changelog)
+	changelog = PrependChangelog(changelog, "## 1.1.0\n")
+	require.Equal(t, "# Changelog\n\n## 1.1.0\n\n"+entry, changelog)
+}
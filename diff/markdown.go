@@ -0,0 +1,38 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Markdown renders the report as a changelog section, grouped by module with breaking changes first
+func (r *Report) Markdown(title string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "## %s\n", title)
+	if len(r.Changes) == 0 {
+		sb.WriteString("\nNo changes.\n")
+		return sb.String()
+	}
+
+	for _, module := range r.Modules() {
+		fmt.Fprintf(&sb, "\n### %s\n", module)
+		for _, group := range []struct {
+			title    string
+			breaking bool
+		}{
+			{"Breaking changes", true},
+			{"Other changes", false},
+		} {
+			var lines []string
+			for _, change := range r.Changes {
+				if change.Module == module && change.Breaking == group.breaking {
+					lines = append(lines, fmt.Sprintf("- %s", change.Message))
+				}
+			}
+			if len(lines) > 0 {
+				fmt.Fprintf(&sb, "\n**%s**\n\n%s\n", group.title, strings.Join(lines, "\n"))
+			}
+		}
+	}
+	return sb.String()
+}
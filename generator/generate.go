@@ -6,16 +6,32 @@ import (
 
 	"github.com/coze-dev/coze-sdk-gen/consts"
 	"github.com/coze-dev/coze-sdk-gen/generator/python"
+	"github.com/coze-dev/coze-sdk-gen/parser"
 )
 
-func Generate(ctx context.Context, lang string, yamlContent []byte, module string) (map[string]string, error) {
+// Options configures the generated SDK
+type Options struct {
+	Module  string // only generate this module and the shared files it imports
+	DocLang string // language of the documentation, empty to use the descriptions as is
+
+	// Distribution of a standalone SDK, packaging metadata is generated when PackageName is set
+	PackageName    string
+	PackageVersion string
+}
+
+// Generate generates the SDK files of one or more specs, which are merged into a single SDK
+func Generate(ctx context.Context, lang string, specs []parser.Spec, opts Options) (map[string]string, error) {
 	var files map[string]string
 	var err error
 
 	switch lang {
 	case consts.Python:
-		generator := python.Generator{}
-		files, err = generator.Generate(ctx, yamlContent)
+		generator := python.Generator{
+			DocLang: opts.DocLang,
+			Package: python.Package{Name: opts.PackageName, Version: opts.PackageVersion},
+			Module:  opts.Module,
+		}
+		files, err = generator.Generate(ctx, specs)
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate Python SDK: %v", err)
 		}
@@ -23,16 +39,17 @@ func Generate(ctx context.Context, lang string, yamlContent []byte, module strin
 		return nil, fmt.Errorf("unsupported language %q", lang)
 	}
 
-	// Filter files by module if specified
-	if module != "" {
-		filteredFiles := make(map[string]string)
-		for dir, content := range files {
-			if dir == module {
-				filteredFiles[dir] = content
-			}
-		}
-		files = filteredFiles
-	}
-
 	return files, nil
 }
+
+// NewParser creates the parser the SDK of a language is generated with, so the specs are read as the
+// SDK presents them
+func NewParser(lang string) (*parser.Parser, error) {
+	switch lang {
+	case consts.Python:
+		generator := python.Generator{}
+		return generator.NewParser()
+	default:
+		return nil, fmt.Errorf("unsupported language %q", lang)
+	}
+}
@@ -0,0 +1,176 @@
+package python
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/coze-dev/coze-sdk-gen/parser"
+)
+
+// pythonKeywords can not be used as identifiers at all
+var pythonKeywords = newNameSet(
+	"False", "None", "True", "and", "as", "assert", "async", "await", "break", "class", "continue",
+	"def", "del", "elif", "else", "except", "finally", "for", "from", "global", "if", "import", "in",
+	"is", "lambda", "nonlocal", "not", "or", "pass", "raise", "return", "try", "while", "with", "yield",
+)
+
+// pythonBuiltins are shadowed by parameters of the same name for the whole method body
+var pythonBuiltins = newNameSet(
+	"abs", "all", "any", "ascii", "bin", "bool", "breakpoint", "bytearray", "bytes", "callable", "chr",
+	"classmethod", "compile", "complex", "delattr", "dict", "dir", "divmod", "enumerate", "eval", "exec",
+	"filter", "float", "format", "frozenset", "getattr", "globals", "hasattr", "hash", "help", "hex", "id",
+	"input", "int", "isinstance", "issubclass", "iter", "len", "list", "locals", "map", "max", "memoryview",
+	"min", "next", "object", "oct", "open", "ord", "pow", "print", "property", "range", "repr", "reversed",
+	"round", "set", "setattr", "slice", "sorted", "staticmethod", "str", "sum", "super", "tuple", "type",
+	"vars", "zip",
+)
+
+// methodLocals are the names the generated methods use besides their parameters, see sdk.tmpl
+var methodLocals = newNameSet(
+	"self", "url", "headers", "body", "multipart", "response", "request_maker", "i_page_num", "i_page_size", "warnings",
+)
+
+// pydanticAttributes are the attributes of pydantic models that fields must not override
+var pydanticAttributes = newNameSet(
+	"model_config", "model_fields", "model_computed_fields", "model_extra", "model_fields_set",
+	"model_construct", "model_copy", "model_dump", "model_dump_json", "model_json_schema",
+	"model_parametrized_name", "model_post_init", "model_rebuild", "model_validate", "model_validate_json",
+	"model_validate_strings", "construct", "copy", "dict", "from_orm", "json", "parse_file", "parse_obj",
+	"parse_raw", "schema", "schema_json", "update_forward_refs", "validate",
+)
+
+// moduleImports are the names the generated modules import that are not capitalized, see
+// sdk.tmpl and pythonFormatImports
+var moduleImports = newNameSet(
+	"base64", "date", "datetime", "deprecated", "httpx", "json", "os", "overload", "quote", "re", "warnings",
+)
+
+// Reserved names by kind of identifier. Fields shadow builtins and imports for the annotations
+// of the fields following them in the class body. Methods and enum members are only looked up
+// as attributes.
+var (
+	reservedFieldNames  = []map[string]bool{pythonKeywords, pythonBuiltins, moduleImports, pydanticAttributes}
+	reservedParamNames  = []map[string]bool{pythonKeywords, pythonBuiltins, methodLocals}
+	reservedMethodNames = []map[string]bool{pythonKeywords}
+	reservedEnumNames   = []map[string]bool{pythonKeywords}
+)
+
+func newNameSet(names ...string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[name] = true
+	}
+	return set
+}
+
+// escapeName appends an underscore to reserved names, e.g. from -> from_
+func escapeName(name string, reserved []map[string]bool) string {
+	for _, set := range reserved {
+		if set[name] {
+			return name + "_"
+		}
+	}
+	return name
+}
+
+func (g *Generator) toPythonFieldName(name string) string {
+	return escapeName(ToPythonVarName(name), reservedFieldNames)
+}
+
+func (g *Generator) toPythonParamName(name string) string {
+	return escapeName(ToPythonVarName(name), reservedParamNames)
+}
+
+// enumMemberName names an enum member, members without x-coze-enum-names are named after their value
+func (g *Generator) enumMemberName(value parser.TyEnumValue) string {
+	name := value.Name
+	if name == "" {
+		name = fmt.Sprint(value.Val)
+		if strings.HasPrefix(name, "-") {
+			name = "minus_" + name[1:]
+		}
+	}
+	name = g.toEnumName(name)
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "VALUE_" + name
+	}
+	return escapeName(name, reservedEnumNames)
+}
+
+// checkIdentifiers reports names that are different in the spec but generated as the same
+// Python identifier: fields of a type, parameters of an operation, operations of a module and
+// members of an enum
+func (g *Generator) checkIdentifiers(modules map[string]*parser.Module) error {
+	moduleNames := make([]string, 0, len(modules))
+	for moduleName := range modules {
+		moduleNames = append(moduleNames, moduleName)
+	}
+	sort.Strings(moduleNames)
+
+	for _, moduleName := range moduleNames {
+		module := modules[moduleName]
+		g.moduleName = moduleName
+
+		for _, ty := range module.Types {
+			if !ty.IsNamed {
+				continue
+			}
+			members := newIdentifiers("enum member", ty.Name)
+			for _, value := range ty.EnumValues {
+				if err := members.add(g.enumMemberName(value), fmt.Sprint(value.Val)); err != nil {
+					return err
+				}
+			}
+			fields := newIdentifiers("field", ty.Name)
+			for _, field := range ty.Fields {
+				if err := fields.add(g.toPythonFieldName(field.Name), field.Name); err != nil {
+					return err
+				}
+			}
+		}
+
+		methods := newIdentifiers("operation", moduleName)
+		for _, handler := range module.HttpHandlers {
+			if err := methods.add(g.toPythonMethodName(handler.Name), handler.Name); err != nil {
+				return err
+			}
+			params := newIdentifiers("parameter", handler.Name)
+			for _, fields := range [][]parser.TyField{handler.PathParams, handler.QueryParams, handler.HeaderParams, handler.CookieParams} {
+				for _, field := range fields {
+					if err := params.add(g.toPythonParamName(field.Name), field.Name); err != nil {
+						return err
+					}
+				}
+			}
+			// Body fields become parameters unless the body is passed as a model
+			if handler.RequestBody != nil && handler.BodyStyle != parser.BodyStyleModel {
+				for _, field := range handler.RequestBody.Fields {
+					if err := params.add(g.toPythonParamName(field.Name), field.Name); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// identifiers maps the generated identifiers of a scope to the names they were generated from
+type identifiers struct {
+	kind  string
+	scope string
+	names map[string]string
+}
+
+func newIdentifiers(kind, scope string) *identifiers {
+	return &identifiers{kind: kind, scope: scope, names: make(map[string]string)}
+}
+
+func (ids *identifiers) add(identifier, name string) error {
+	if existing, ok := ids.names[identifier]; ok && existing != name {
+		return fmt.Errorf("%s: %s %s and %s are both generated as %s", ids.scope, ids.kind, existing, name, identifier)
+	}
+	ids.names[identifier] = name
+	return nil
+}
@@ -0,0 +1,110 @@
+package python
+
+import (
+	"testing"
+
+	"github.com/coze-dev/coze-sdk-gen/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func stringTy() *parser.Ty {
+	return &parser.Ty{Kind: parser.TyKindPrimitive, PrimitiveKind: parser.PrimitiveString}
+}
+
+func TestNames(t *testing.T) {
+	g := &Generator{}
+	tests := []struct {
+		name  string
+		field string
+		param string
+	}{
+		{name: "from", field: "from_", param: "from_"},
+		{name: "botId", field: "bot_id", param: "bot_id"},
+		{name: "list", field: "list_", param: "list_"},
+		{name: "json", field: "json_", param: "json"},
+		{name: "model_dump", field: "model_dump_", param: "model_dump"},
+		{name: "url", field: "url", param: "url_"},
+		{name: "from_", field: "from_", param: "from_"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.field, g.toPythonFieldName(tt.name))
+			require.Equal(t, tt.param, g.toPythonParamName(tt.name))
+		})
+	}
+}
+
+func TestEnumMemberName(t *testing.T) {
+	g := &Generator{}
+	tests := []struct {
+		value parser.TyEnumValue
+		want  string
+	}{
+		{value: parser.TyEnumValue{Val: -1}, want: "MINUS_1"},
+		{value: parser.TyEnumValue{Val: 1}, want: "VALUE_1"},
+		{value: parser.TyEnumValue{Val: 0, Name: "draft"}, want: "DRAFT"},
+		{value: parser.TyEnumValue{Val: "chatFlow"}, want: "CHAT_FLOW"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			require.Equal(t, tt.want, g.enumMemberName(tt.value))
+		})
+	}
+}
+
+func TestCheckIdentifiers(t *testing.T) {
+	tests := []struct {
+		name   string
+		module *parser.Module
+		err    string
+	}{
+		{
+			name: "fields",
+			module: &parser.Module{Types: []*parser.Ty{{
+				Name:    "Message",
+				Kind:    parser.TyKindObject,
+				IsNamed: true,
+				Fields:  []parser.TyField{{Name: "from", Type: stringTy()}, {Name: "from_", Type: stringTy()}},
+			}}},
+			err: "Message: field from and from_ are both generated as from_",
+		},
+		{
+			name: "enum members",
+			module: &parser.Module{Types: []*parser.Ty{{
+				Name:       "Status",
+				Kind:       parser.TyKindPrimitive,
+				IsNamed:    true,
+				EnumValues: []parser.TyEnumValue{{Val: -1}, {Val: 1}, {Val: 2, Name: "minus_1"}},
+			}}},
+			err: "Status: enum member -1 and 2 are both generated as MINUS_1",
+		},
+		{
+			name: "parameters",
+			module: &parser.Module{HttpHandlers: []parser.HttpHandler{{
+				Name:        "ListMessages",
+				QueryParams: []parser.TyField{{Name: "from", Type: stringTy()}, {Name: "from_", Type: stringTy()}},
+			}}},
+			err: "ListMessages: parameter from and from_ are both generated as from_",
+		},
+		{
+			name: "distinct",
+			module: &parser.Module{Types: []*parser.Ty{{
+				Name:       "Status",
+				Kind:       parser.TyKindPrimitive,
+				IsNamed:    true,
+				EnumValues: []parser.TyEnumValue{{Val: -1}, {Val: 1}},
+			}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &Generator{}
+			err := g.checkIdentifiers(map[string]*parser.Module{"messages": tt.module})
+			if tt.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tt.err)
+		})
+	}
+}
@@ -7,15 +7,21 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/fs"
+	"path"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"text/template"
 
+	"github.com/coze-dev/coze-sdk-gen/markdown"
 	"github.com/coze-dev/coze-sdk-gen/parser"
+	"github.com/coze-dev/coze-sdk-gen/util"
+	"golang.org/x/exp/slices"
 	"gopkg.in/yaml.v3"
 )
 
-//go:embed templates/sdk.tmpl
+//go:embed templates/*.tmpl
 var templateFS embed.FS
 
 //go:embed config.yaml
@@ -35,17 +41,51 @@ type ModuleConfig struct {
 	TypeMapping               map[string]string               `yaml:"type_mapping"`
 	SkipOptionalFieldsClasses []string                        `yaml:"skip_optional_fields_classes"`
 	PagedOperations           map[string]PagedOperationConfig `yaml:"paged_operations"`
+	BodyStyles                map[string]parser.BodyStyle     `yaml:"body_styles"`
+	// SplitModels are split into Xxx without writeOnly fields for responses and XxxCreate
+	// without readOnly fields for requests. Other models drop writeOnly fields when no request
+	// sends them, models sent in requests too keep readOnly and writeOnly fields as optional.
+	SplitModels []string `yaml:"split_models"`
 }
 
 type Config struct {
 	Modules map[string]ModuleConfig `yaml:"modules"`
 }
 
+// Package is the distribution metadata of a standalone SDK package
+type Package struct {
+	Name    string
+	Version string
+}
+
+// Documentation languages, the descriptions of the spec are used as is by default
+const (
+	DocLangEn   = parser.LangEn
+	DocLangZh   = parser.LangZh
+	DocLangBoth = "both"
+)
+
 // Generator handles Python SDK generation using parser2
 type Generator struct {
-	classes    []PythonClass
-	config     Config
-	moduleName string
+	// DocLang selects the language of the docstrings: DocLangEn, DocLangZh or DocLangBoth
+	DocLang string
+	// Package is the metadata of the pyproject.toml generated for standalone SDKs, if named
+	Package Package
+	// Module restricts the generated files to one module and the shared files it imports
+	Module string
+
+	classes         []PythonClass
+	config          Config
+	moduleName      string
+	securitySchemes map[string]*parser.SecurityScheme
+	// servers are the base urls of the environments, the first one is the default
+	servers []PythonServer
+	// formats used by the current module
+	formats map[parser.PrimitiveKind]bool
+	// request is set while converting request types, which use the XxxCreate split models
+	request bool
+	// requestTypes are the named types sent in requests, the others need no writeOnly fields
+	requestTypes map[string]bool
 }
 
 // pythonTypeMapping maps our types to Python types
@@ -56,6 +96,39 @@ var pythonTypeMapping = map[parser.PrimitiveKind]string{
 	parser.PrimitiveBool:    "bool",
 	parser.PrimitiveBinary:  "bytes",
 	parser.PrimitiveUnknown: "Any",
+	// formats, UnixTimestamp, Int64String and Base64Bytes are annotated aliases defined in the module
+	parser.PrimitiveInt64:         "int",
+	parser.PrimitiveDateTime:      "datetime",
+	parser.PrimitiveDate:          "date",
+	parser.PrimitiveUUID:          "UUID",
+	parser.PrimitiveURI:           "str",
+	parser.PrimitiveByte:          "Base64Bytes",
+	parser.PrimitiveDecimal:       "Decimal",
+	parser.PrimitiveUnixTimestamp: "UnixTimestamp",
+	parser.PrimitiveInt64String:   "Int64String",
+}
+
+// pythonFormatImports are the imports needed by the Python types of formats
+var pythonFormatImports = map[parser.PrimitiveKind][]string{
+	parser.PrimitiveDateTime:      {"from datetime import datetime"},
+	parser.PrimitiveDate:          {"from datetime import date"},
+	parser.PrimitiveUUID:          {"from uuid import UUID"},
+	parser.PrimitiveByte:          {"import base64", "from pydantic import BeforeValidator, PlainSerializer", "from typing_extensions import Annotated"},
+	parser.PrimitiveDecimal:       {"from decimal import Decimal"},
+	parser.PrimitiveUnixTimestamp: {"from datetime import datetime", "from pydantic import PlainSerializer", "from typing_extensions import Annotated"},
+	parser.PrimitiveInt64String:   {"from pydantic import PlainSerializer", "from typing_extensions import Annotated"},
+}
+
+// pythonWireFormats are the formats whose Python values are converted before being sent as
+// parameters, models are converted by pydantic
+var pythonWireFormats = map[parser.PrimitiveKind]bool{
+	parser.PrimitiveDateTime:      true,
+	parser.PrimitiveDate:          true,
+	parser.PrimitiveUUID:          true,
+	parser.PrimitiveByte:          true,
+	parser.PrimitiveDecimal:       true,
+	parser.PrimitiveUnixTimestamp: true,
+	parser.PrimitiveInt64String:   true,
 }
 
 // PythonClass represents a Python class
@@ -69,6 +142,10 @@ type PythonClass struct {
 	EnumValues  []PythonEnumValue
 	ShouldSkip  bool
 	IsPass      bool
+	// HasAliases is set when fields are aliased, which must also be populated by their names
+	HasAliases bool
+	// DeprecationMessage is set for deprecated models, which warn when instantiated
+	DeprecationMessage string
 }
 
 // PythonEnumValue represents a Python enum value
@@ -85,6 +162,8 @@ type PythonField struct {
 	Description string
 	IsMethod    bool
 	Default     string
+	// FieldArgs are the arguments of a pydantic Field assigned to the field, starting with the default
+	FieldArgs []string
 }
 
 // PythonOperation represents a Python API operation
@@ -104,6 +183,8 @@ type PythonOperation struct {
 	HeaderParams        []PythonParam
 	HasHeaders          bool
 	StaticHeaders       map[string]string
+	CookieParams        []PythonParam
+	HasCookies          bool
 	// page
 	IsPaged           bool
 	ResponseCast      string
@@ -111,6 +192,70 @@ type PythonOperation struct {
 	PageIndexName     string
 	PageSizeName      string
 	HasFileUpload     bool
+	// body style
+	BodyStyle     string
+	BodyModel     string
+	BodyParamName string
+	BodyExclude   string // Python set of the readOnly fields of a shared body model
+	ModelParams   []PythonParam
+	ImplParams    []PythonParam
+	// response kind
+	ResponseKind  string
+	IsRawResponse bool
+	// auth
+	AuthHeaders      []PythonAuthParam
+	AuthQueryParams  []PythonAuthParam
+	AuthCookieParams []PythonAuthParam
+	AuthOptional     bool
+	// AuthNote documents the OAuth scopes the operation requires and where tokens are issued
+	AuthNote string
+	// BaseURL overrides the client base url when the operation declares its own servers
+	BaseURL string
+	// HasServerMap is set when BaseURL picks the server of the environment of the client
+	HasServerMap bool
+	// params
+	ReservedQueryParams []PythonParam
+	HasStyledParams     bool
+	// DeprecationMessage is set for deprecated operations, DeprecatedParams warn when passed
+	DeprecationMessage string
+	DeprecatedParams   []PythonParam
+	// ValidatedParams are checked against their constraints before the request is sent
+	ValidatedParams []PythonParam
+	// Example is a call of the method with the examples of the parameters
+	Example string
+	// ResponseExamples are the response examples, deserialized by the generated tests
+	ResponseExamples []PythonResponseExample
+}
+
+// PythonResponseExample is a response example and the type it is deserialized to
+type PythonResponseExample struct {
+	ID      string
+	Cast    string
+	Payload string
+	Imports []string // classes of the module used by Cast
+}
+
+// PythonServer represents a base URL constant and its environment enum member
+type PythonServer struct {
+	ConstName   string
+	EnumName    string
+	URL         string
+	Description string
+	// Values are the values of the server variables substituted in URL
+	Values map[string]string
+}
+
+// PythonClientModule represents a module client wired into the root client
+type PythonClientModule struct {
+	ModuleName string
+	ClientName string
+	AttrName   string
+}
+
+// PythonAuthParam represents a credential sent with a request
+type PythonAuthParam struct {
+	Name  string
+	Value string
 }
 
 // PythonParam represents a Python parameter
@@ -122,13 +267,43 @@ type PythonParam struct {
 	DefaultValue string
 	HasDefault   bool
 	IsModel      bool
+	// Serialization of array and object parameters
+	Style     string
+	Explode   bool
+	Serialize bool
+	// MultipartKind is how a multipart body field is sent: "file", "files" or "form"
+	MultipartKind string
+	// DeprecationMessage is set for deprecated parameters
+	DeprecationMessage string
+	// Constraints are the keyword arguments validating the value, e.g. ge=1, le=100
+	Constraints string
+	// Value is the expression sent for the parameter, which converts formatted values such as datetimes
+	Value string
+	// Example is a Python literal of the first example of the parameter
+	Example string
 }
 
 // PythonModule represents a converted Python module
 type PythonModule struct {
-	Operations    []PythonOperation
-	Classes       []PythonClass
-	HasFileUpload bool
+	Operations      []PythonOperation
+	Classes         []PythonClass
+	HasFileUpload   bool
+	HasOverloads    bool
+	HasRawResponse  bool
+	HasAnonymous    bool
+	HasStyledParams bool
+	HasServerMaps   bool
+	HasKwargsBody   bool // bodies built from keyword arguments
+	HasDeprecated   bool
+	HasConstraints  bool
+	HasFieldArgs    bool
+	HasAliases      bool
+	// Formats are the formats used by the module, FormatImports the imports they need
+	Formats            map[string]bool
+	FormatImports      []string
+	HasFormattedParams bool
+	// Exports are the public names of the module listed in __all__: models and clients
+	Exports []string
 }
 
 func (g *Generator) loadConfig() error {
@@ -144,14 +319,14 @@ func (g *Generator) loadConfig() error {
 	return nil
 }
 
-// Generate generates Python SDK code from parsed OpenAPI data
-func (g *Generator) Generate(ctx context.Context, yamlContent []byte) (map[string]string, error) {
+// NewParser loads the config and creates the parser of the specs, with the renames and changes
+// the generated SDK makes to them
+func (g *Generator) NewParser() (*parser.Parser, error) {
 	// Load config first
 	if err := g.loadConfig(); err != nil {
 		return nil, err
 	}
 
-	// Create new parser2 instance
 	p, err := parser.NewParser(&parser.ModuleConfig{
 		GenerateUnnamedResponseType: func(h *parser.HttpHandler) (string, bool) {
 			if h.GetActualResponseBody() == nil {
@@ -160,6 +335,10 @@ func (g *Generator) Generate(ctx context.Context, yamlContent []byte) (map[strin
 
 			return "", false
 		},
+		GenerateUnnamedRequestType: func(h *parser.HttpHandler) (string, bool) {
+			return fmt.Sprintf("%sReq", h.Name), true
+		},
+		BodyStyles: g.bodyStyles(),
 		ChangeHttpHandlerResponseType: map[string]string{
 			"CreateDraftBot":  "Bot",
 			"UpdateDraftBot":  "Bot",
@@ -186,91 +365,475 @@ func (g *Generator) Generate(ctx context.Context, yamlContent []byte) (map[strin
 	if err != nil {
 		return nil, fmt.Errorf("create parser2 failed: %w", err)
 	}
+	return p, nil
+}
+
+// Generate generates Python SDK code from parsed OpenAPI data
+func (g *Generator) Generate(ctx context.Context, specs []parser.Spec) (map[string]string, error) {
+	p, err := g.NewParser()
+	if err != nil {
+		return nil, err
+	}
 
 	// Parse OpenAPI spec
-	modules, err := p.ParseOpenAPI(yamlContent)
+	modules, err := p.ParseOpenAPISpecs(specs)
 	if err != nil {
 		return nil, fmt.Errorf("parse OpenAPI failed: %w", err)
 	}
 
-	// Generate code for each module
+	if err := g.checkIdentifiers(modules); err != nil {
+		return nil, err
+	}
+	if _, ok := modules[g.Module]; g.Module != "" && !ok {
+		return nil, fmt.Errorf("module %s not found", g.Module)
+	}
+
+	g.servers = g.convertServers(p.Servers())
+	g.requestTypes = requestTypes(modules)
+
+	g.securitySchemes = make(map[string]*parser.SecurityScheme)
+	for _, scheme := range p.SecuritySchemes() {
+		g.securitySchemes[scheme.Name] = scheme
+	}
+
+	// Generate code for each module, files are keyed by their slash separated path in the package
 	files := make(map[string]string)
+	// packages are the generated packages below the root, in dotted form
+	var packages []string
 
 	// Read template
 	tmpl, err := template.New("python").Funcs(template.FuncMap{
-		"title": func(x string) string {
-			return strings.ReplaceAll(strings.Title(x), ".", "")
+		"title": toClassName,
+		"method": func(op PythonOperation, async bool) map[string]interface{} {
+			return map[string]interface{}{"Op": op, "Async": async}
+		},
+		"signature": func(op PythonOperation, params []PythonParam, async bool) map[string]interface{} {
+			return map[string]interface{}{"Op": op, "Params": params, "Async": async}
+		},
+		"query": func(param PythonParam, value string) map[string]interface{} {
+			return map[string]interface{}{"Param": param, "Value": value}
+		},
+		"pyBool": func(b bool) string {
+			return util.Choose(b, "True", "False")
+		},
+		"auth": func(params []PythonAuthParam, optional bool) map[string]interface{} {
+			return map[string]interface{}{"Params": params, "Optional": optional}
+		},
+		"pyStr": strconv.Quote,
+		"join":  strings.Join,
+		"docstring": func(op PythonOperation, params []PythonParam) map[string]interface{} {
+			return map[string]interface{}{
+				"Description":         op.Description,
+				"DeprecationMessage":  op.DeprecationMessage,
+				"AuthNote":            op.AuthNote,
+				"Example":             op.Example,
+				"Params":              params,
+				"ResponseDescription": op.ResponseDescription,
+			}
 		},
-	}).Parse(g.getTemplate())
+	}).Parse(g.getTemplate("templates/sdk.tmpl"))
 	if err != nil {
 		return nil, fmt.Errorf("parse template failed: %w", err)
 	}
 
 	// Convert modules to Python-specific format
 	for moduleName, module := range modules {
+		if g.Module != "" && moduleName != g.Module {
+			continue
+		}
 		pythonModule := g.convertModule(module)
 		var buf bytes.Buffer
 		err = tmpl.Execute(&buf, map[string]interface{}{
-			"ModuleName":    moduleName,
-			"Operations":    pythonModule.Operations,
-			"Classes":       pythonModule.Classes,
-			"HasFileUpload": pythonModule.HasFileUpload,
+			"ModuleName":         moduleName,
+			"Operations":         pythonModule.Operations,
+			"Classes":            pythonModule.Classes,
+			"HasFileUpload":      pythonModule.HasFileUpload,
+			"HasOverloads":       pythonModule.HasOverloads,
+			"HasRawResponse":     pythonModule.HasRawResponse,
+			"HasAnonymous":       pythonModule.HasAnonymous,
+			"HasStyledParams":    pythonModule.HasStyledParams,
+			"HasServerMaps":      pythonModule.HasServerMaps,
+			"HasKwargsBody":      pythonModule.HasKwargsBody,
+			"HasDeprecated":      pythonModule.HasDeprecated,
+			"HasConstraints":     pythonModule.HasConstraints,
+			"HasFieldArgs":       pythonModule.HasFieldArgs,
+			"HasAliases":         pythonModule.HasAliases,
+			"Formats":            pythonModule.Formats,
+			"FormatImports":      pythonModule.FormatImports,
+			"HasFormattedParams": pythonModule.HasFormattedParams,
+			"Exports":            pythonModule.Exports,
+			"RootPackage":        strings.Repeat(".", strings.Count(moduleName, ".")+2),
 		})
 		if err != nil {
 			return nil, fmt.Errorf("execute template failed: %w", err)
 		}
-		files[fmt.Sprintf("%s", moduleName)] = buf.String()
+		files[path.Join(strings.ReplaceAll(moduleName, ".", "/"), "__init__.py")] = buf.String()
+		packages = append(packages, moduleName)
+
+		// Generate the tests deserializing the response examples
+		tests, err := g.generateExampleTests(moduleName, pythonModule)
+		if err != nil {
+			return nil, err
+		}
+		if tests != "" {
+			if !slices.Contains(packages, "tests") {
+				packages = append(packages, "tests")
+			}
+			files["tests/__init__.py"] = ""
+			files[fmt.Sprintf("tests/test_%s_examples.py", strings.ReplaceAll(moduleName, ".", "_"))] = tests
+		}
+	}
+
+	// Generate the root client wiring all module clients
+	if g.Module == "" {
+		client, err := g.generateClient(modules)
+		if err != nil {
+			return nil, err
+		}
+		files["__init__.py"] = client
+	}
+
+	// Files shared by the modules, py.typed marks the package as typed
+	files["_auth.py"] = g.getTemplate("templates/auth.tmpl")
+	files["py.typed"] = ""
+	if g.Package.Name != "" {
+		pyproject, err := g.generatePyproject(packages)
+		if err != nil {
+			return nil, err
+		}
+		files["pyproject.toml"] = pyproject
 	}
 
 	return files, nil
 }
 
+// generatePyproject generates the pyproject.toml packaging the generated files, which are the
+// package itself, as a standalone distribution of the root package and the given packages below it
+func (g *Generator) generatePyproject(packages []string) (string, error) {
+	importName := ToPythonVarName(g.Package.Name)
+	names := []string{importName}
+	for _, pkg := range packages {
+		// Parents of nested modules are namespace packages which are listed too
+		parts := strings.Split(pkg, ".")
+		for i := range parts {
+			if name := importName + "." + strings.Join(parts[:i+1], "."); !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+
+	tmpl, err := template.New("pyproject").Parse(g.getTemplate("templates/pyproject.tmpl"))
+	if err != nil {
+		return "", fmt.Errorf("parse pyproject template failed: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, map[string]interface{}{
+		"Name":       g.Package.Name,
+		"Version":    util.Choose(g.Package.Version != "", g.Package.Version, "0.1.0"),
+		"ImportName": importName,
+		"Packages":   names,
+	}); err != nil {
+		return "", fmt.Errorf("execute pyproject template failed: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// convertServers expands the servers into the environments users choose from, one for each
+// combination of the enum values of the server variables. URLs declared twice are kept once.
+func (g *Generator) convertServers(servers []parser.Server) []PythonServer {
+	var pythonServers []PythonServer
+	urls := make(map[string]bool)
+	names := make(map[string]int)
+	for _, server := range servers {
+		for _, serverURL := range server.URLs() {
+			if urls[serverURL.URL] {
+				continue
+			}
+			urls[serverURL.URL] = true
+
+			enumName := strings.ToUpper(g.toPythonVarName(serverURL.Name))
+			names[enumName]++
+			if count := names[enumName]; count > 1 {
+				enumName = fmt.Sprintf("%s_%d", enumName, count)
+			}
+			pythonServers = append(pythonServers, PythonServer{
+				ConstName:   enumName + "_BASE_URL",
+				EnumName:    enumName,
+				URL:         serverURL.URL,
+				Description: g.formatDescription(server.Description),
+				Values:      serverURL.Values,
+			})
+		}
+	}
+	return pythonServers
+}
+
+// generateClient generates the root package with base url constants, the environment enum
+// and the root clients
+func (g *Generator) generateClient(modules map[string]*parser.Module) (string, error) {
+	tmpl, err := template.New("client").Parse(g.getTemplate("templates/client.tmpl"))
+	if err != nil {
+		return "", fmt.Errorf("parse client template failed: %w", err)
+	}
+
+	moduleNames := make([]string, 0, len(modules))
+	for moduleName := range modules {
+		moduleNames = append(moduleNames, moduleName)
+	}
+	sort.Strings(moduleNames)
+
+	clientModules := make([]PythonClientModule, 0, len(moduleNames))
+	for _, moduleName := range moduleNames {
+		clientModules = append(clientModules, PythonClientModule{
+			ModuleName: moduleName,
+			ClientName: toClassName(moduleName) + "Client",
+			AttrName:   g.toPythonVarName(moduleName),
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, map[string]interface{}{
+		"Servers": g.servers,
+		"Modules": clientModules,
+	}); err != nil {
+		return "", fmt.Errorf("execute client template failed: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// generateExampleTests generates the pytest module deserializing the response examples of a
+// module, or "" if it has none
+func (g *Generator) generateExampleTests(moduleName string, module PythonModule) (string, error) {
+	var examples []PythonResponseExample
+	var imports []string
+	for _, op := range module.Operations {
+		for _, example := range op.ResponseExamples {
+			examples = append(examples, example)
+			for _, name := range example.Imports {
+				if !slices.Contains(imports, name) {
+					imports = append(imports, name)
+				}
+			}
+		}
+	}
+	if len(examples) == 0 {
+		return "", nil
+	}
+	sort.Strings(imports)
+
+	tmpl, err := template.New("tests").Funcs(template.FuncMap{
+		"pyStr": strconv.Quote,
+		"join":  strings.Join,
+	}).Parse(g.getTemplate("templates/tests.tmpl"))
+	if err != nil {
+		return "", fmt.Errorf("parse tests template failed: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, map[string]interface{}{
+		"ModuleName": moduleName,
+		"Imports":    imports,
+		"Examples":   examples,
+	}); err != nil {
+		return "", fmt.Errorf("execute tests template failed: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// toClassName converts a module name to the prefix of its client class name
+func toClassName(moduleName string) string {
+	return strings.ReplaceAll(strings.Title(moduleName), ".", "")
+}
+
+// bodyStyles collects the configured body styles of all modules, keyed by operation id
+func (g *Generator) bodyStyles() map[string]parser.BodyStyle {
+	styles := make(map[string]parser.BodyStyle)
+	for _, moduleConfig := range g.config.Modules {
+		for operationID, style := range moduleConfig.BodyStyles {
+			styles[operationID] = style
+		}
+	}
+	return styles
+}
+
 func (g *Generator) convertModule(module *parser.Module) PythonModule {
 	// Store current module name
 	g.moduleName = module.Name
+	g.formats = make(map[parser.PrimitiveKind]bool)
 
 	// Convert types to classes
 	classes := make([]PythonClass, 0)
 	for _, ty := range module.Types {
-		if pythonClass := g.convertType(ty); pythonClass != nil {
+		if pythonClass := g.convertType(ty, false); pythonClass != nil {
 			classes = append(classes, *pythonClass)
 		}
+		if len(ty.EnumValues) == 0 && g.isSplitModel(ty.Name) {
+			g.request = true
+			if pythonClass := g.convertType(ty, true); pythonClass != nil {
+				classes = append(classes, *pythonClass)
+			}
+			g.request = false
+		}
 	}
 	g.classes = classes
 
 	// Convert operations
 	operations := make([]PythonOperation, 0)
 	hasFileUpload := false
+	hasOverloads := false
+	hasRawResponse := false
+	hasAnonymous := false
+	hasStyledParams := false
+	hasServerMaps := false
+	hasKwargsBody := false
+	hasDeprecated := false
+	hasConstraints := false
+	hasFieldArgs := false
+	hasAliases := false
+	hasFormattedParams := false
+	for _, class := range classes {
+		if class.HasAliases {
+			hasAliases = true
+		}
+		if class.DeprecationMessage != "" {
+			hasDeprecated = true
+		}
+		for _, field := range class.Fields {
+			if len(field.FieldArgs) > 0 {
+				hasFieldArgs = true
+			}
+		}
+	}
 	for _, handler := range module.HttpHandlers {
 		if op := g.convertHandler(&handler); op != nil {
 			operations = append(operations, *op)
 			if op.HasFileUpload {
 				hasFileUpload = true
 			}
+			if op.BodyStyle == string(parser.BodyStyleBoth) {
+				hasOverloads = true
+			}
+			if op.IsRawResponse {
+				hasRawResponse = true
+			}
+			if op.AuthOptional {
+				hasAnonymous = true
+			}
+			if op.HasStyledParams {
+				hasStyledParams = true
+			}
+			if op.HasServerMap {
+				hasServerMaps = true
+			}
+			if op.HasBody && !op.HasFileUpload && op.BodyStyle != string(parser.BodyStyleModel) {
+				hasKwargsBody = true
+			}
+			if op.DeprecationMessage != "" || len(op.DeprecatedParams) > 0 {
+				hasDeprecated = true
+			}
+			if len(op.ValidatedParams) > 0 {
+				hasConstraints = true
+			}
+			for _, param := range op.Params {
+				if param.Value != param.Name {
+					hasFormattedParams = true
+				}
+			}
+		}
+	}
+
+	formats := make(map[string]bool)
+	for kind := range g.formats {
+		formats[string(kind)] = true
+	}
+
+	var exports []string
+	for _, class := range classes {
+		if !class.ShouldSkip && !strings.HasPrefix(class.Name, "_") {
+			exports = append(exports, class.Name)
 		}
 	}
+	if hasRawResponse {
+		exports = append(exports, "BinaryResponse", "AsyncBinaryResponse")
+	}
+	exports = append(exports, toClassName(module.Name)+"Client", "Async"+toClassName(module.Name)+"Client")
 
 	return PythonModule{
-		Operations:    operations,
-		Classes:       classes,
-		HasFileUpload: hasFileUpload,
+		Operations:         operations,
+		Classes:            classes,
+		HasFileUpload:      hasFileUpload,
+		HasOverloads:       hasOverloads,
+		HasRawResponse:     hasRawResponse,
+		HasAnonymous:       hasAnonymous,
+		HasStyledParams:    hasStyledParams,
+		HasServerMaps:      hasServerMaps,
+		HasKwargsBody:      hasKwargsBody,
+		HasDeprecated:      hasDeprecated,
+		HasConstraints:     hasConstraints,
+		HasFieldArgs:       hasFieldArgs,
+		HasAliases:         hasAliases,
+		Formats:            formats,
+		FormatImports:      formatImports(g.formats),
+		HasFormattedParams: hasFormattedParams,
+		Exports:            exports,
+	}
+}
+
+// formatImports returns the sorted imports needed by the formats used in a module, names
+// imported from the same module are merged, e.g. from datetime import date, datetime
+func formatImports(formats map[parser.PrimitiveKind]bool) []string {
+	names := make(map[string][]string)
+	for kind := range formats {
+		for _, imp := range pythonFormatImports[kind] {
+			from, imported, _ := strings.Cut(imp, " import ")
+			if !strings.HasPrefix(imp, "from ") {
+				from, imported = imp, ""
+			}
+			for _, name := range strings.Split(imported, ", ") {
+				if !slices.Contains(names[from], name) {
+					names[from] = append(names[from], name)
+				}
+			}
+		}
 	}
+
+	var imports []string
+	for from, fromNames := range names {
+		if !strings.HasPrefix(from, "from ") {
+			imports = append(imports, from)
+			continue
+		}
+		sort.Strings(fromNames)
+		imports = append(imports, fmt.Sprintf("%s import %s", from, strings.Join(fromNames, ", ")))
+	}
+	sort.Strings(imports)
+	return imports
 }
 
-func (g *Generator) convertType(ty *parser.Ty) *PythonClass {
+// convertType converts a named type to a class, request selects the XxxCreate model of split models
+func (g *Generator) convertType(ty *parser.Ty, request bool) *PythonClass {
 	if !ty.IsNamed {
 		return nil
 	}
 
+	sent := g.requestTypes[ty.Name]
+
 	// Apply type mapping if exists
 	if g.config.Modules[g.moduleName].TypeMapping[ty.Name] != "" {
 		ty.Name = g.config.Modules[g.moduleName].TypeMapping[ty.Name]
 	}
+	split := g.isSplitModel(ty.Name)
+	// Models only received from the server drop writeOnly fields, like the responses of split models
+	responseOnly := !split && !sent
 
+	deprecation := deprecationMessage(ty.Name, ty.Deprecation)
 	pythonClass := &PythonClass{
-		Name:        ty.Name,
-		Description: g.formatDescription(ty.Description),
-		BaseClass:   "CozeModel",
+		Name:               ty.Name + util.Choose(request, "Create", ""),
+		Description:        withDeprecationNote(g.formatDescription(g.description(ty.Description, ty.Descriptions)), deprecation),
+		BaseClass:          "CozeModel",
+		DeprecationMessage: deprecation,
 	}
 
 	// Handle enums
@@ -279,7 +842,7 @@ func (g *Generator) convertType(ty *parser.Ty) *PythonClass {
 		pythonClass.BaseClass = "IntEnum"
 		for _, value := range ty.EnumValues {
 			pythonClass.EnumValues = append(pythonClass.EnumValues, PythonEnumValue{
-				Name:  g.toEnumName(value.Name),
+				Name:  g.enumMemberName(value),
 				Value: fmt.Sprintf("%v", value.Val),
 			})
 		}
@@ -299,20 +862,54 @@ func (g *Generator) convertType(ty *parser.Ty) *PythonClass {
 
 	// Convert fields
 	for _, field := range ty.Fields {
+		if split && util.Choose(request, field.ReadOnly, field.WriteOnly) || responseOnly && field.WriteOnly {
+			continue
+		}
+		// A model shared by requests and responses can not require what only one of them sends
+		if !split && !responseOnly && (field.ReadOnly || field.WriteOnly) {
+			field.Required = false
+		}
+
+		// Defaults of the spec make optional fields non-Optional, unless configured otherwise
+		var specDefault string
+		var isFactory, hasSpecDefault bool
+		if field.Default == "" && !field.Required {
+			specDefault, isFactory, hasSpecDefault = g.pythonDefault(field.DefaultValue, field.Type)
+			hasSpecDefault = hasSpecDefault && specDefault != "None"
+		}
+
 		fieldType := g.getFieldType(field.Type)
-		if !field.Required && !skipOptionalFields {
+		if !field.Required && !skipOptionalFields && !hasSpecDefault {
 			fieldType = fmt.Sprintf("Optional[%s]", fieldType)
 		}
 
 		pythonField := PythonField{
-			Name:        g.toPythonVarName(field.Name),
+			Name:        g.toPythonFieldName(field.Name),
 			Type:        fieldType,
-			Description: g.formatDescription(field.Description),
+			Description: withDeprecationNote(g.formatDescription(g.description(field.Description, field.Descriptions)), deprecationMessage(ty.Name+"."+field.Name, field.Deprecation)),
 			Default:     field.Default,
 		}
+		if hasSpecDefault && !isFactory {
+			pythonField.Default = specDefault
+		}
 		if pythonField.Default == "" && !field.Required {
 			pythonField.Default = "None"
 		}
+		args := constraintArgs(field.Constraints, field.Type)
+		if hasSpecDefault && isFactory {
+			// Mutable defaults are created for each instance
+			pythonField.FieldArgs = append([]string{"default_factory=" + specDefault}, args...)
+		} else if len(args) > 0 {
+			pythonField.FieldArgs = append([]string{util.Choose(pythonField.Default != "", pythonField.Default, "...")}, args...)
+		}
+		// Fields named differently than in the spec, e.g. botId or escaped names, are aliased
+		if pythonField.Name != field.Name {
+			if len(pythonField.FieldArgs) == 0 {
+				pythonField.FieldArgs = []string{util.Choose(pythonField.Default != "", pythonField.Default, "...")}
+			}
+			pythonField.FieldArgs = append(pythonField.FieldArgs, "alias="+strconv.Quote(field.Name))
+			pythonClass.HasAliases = true
+		}
 		pythonClass.Fields = append(pythonClass.Fields, pythonField)
 	}
 
@@ -323,6 +920,49 @@ func (g *Generator) convertType(ty *parser.Ty) *PythonClass {
 	return pythonClass
 }
 
+// requestTypes collects the named types sent in the request bodies and parameters of all modules
+func requestTypes(modules map[string]*parser.Module) map[string]bool {
+	types := make(map[string]bool)
+	seen := make(map[*parser.Ty]bool)
+	var collect func(ty *parser.Ty)
+	collect = func(ty *parser.Ty) {
+		if ty == nil || seen[ty] {
+			return
+		}
+		seen[ty] = true
+		if ty.IsNamed {
+			types[ty.Name] = true
+		}
+		for _, field := range ty.Fields {
+			collect(field.Type)
+		}
+		collect(ty.ElementType)
+		collect(ty.ValueType)
+	}
+
+	for _, module := range modules {
+		for _, handler := range module.HttpHandlers {
+			collect(handler.RequestBody)
+			for _, params := range [][]parser.TyField{handler.PathParams, handler.QueryParams, handler.HeaderParams, handler.CookieParams} {
+				for _, param := range params {
+					collect(param.Type)
+				}
+			}
+		}
+	}
+	return types
+}
+
+// isSplitModel checks if a model is split into a response and a request model
+func (g *Generator) isSplitModel(name string) bool {
+	for _, moduleConfig := range g.config.Modules {
+		if slices.Contains(moduleConfig.SplitModels, name) {
+			return true
+		}
+	}
+	return false
+}
+
 func removeOptional(t string) string {
 	if strings.HasPrefix(t, "Optional[") && strings.HasSuffix(t, "]") {
 		return t[9 : len(t)-1]
@@ -338,27 +978,43 @@ func marshal(v any) string {
 func (g *Generator) convertHandler(handler *parser.HttpHandler) *PythonOperation {
 	operation := &PythonOperation{
 		Name:        g.toPythonMethodName(handler.Name),
-		Description: handler.Description,
+		Description: g.formatDescription(g.description(handler.Description, handler.Descriptions)),
 		Path:        handler.Path,
 		Method:      strings.ToUpper(handler.Method),
 	}
 
+	// Parameters and the request body use the request models
+	g.request = true
+
 	// Convert parameters
 	var headerParams []PythonParam
+	var nonBodyParams []PythonParam
 	staticHeaders := make(map[string]string)
 
 	// Handle path parameters
+	pathParams := make(map[string]PythonParam)
 	for _, param := range handler.PathParams {
 		pythonParam := g.convertParam(&param)
 		operation.Params = append(operation.Params, pythonParam)
+		pathParams[param.Name] = pythonParam
+	}
+	if len(pathParams) > 0 {
+		operation.Path = g.toPythonPath(handler.Path, pathParams)
+		operation.HasStyledParams = true
 	}
 
 	// Handle query parameters
 	for _, param := range handler.QueryParams {
 		pythonParam := g.convertParam(&param)
-		operation.QueryParams = append(operation.QueryParams, pythonParam)
+		if param.AllowReserved {
+			// reserved characters must not be percent-encoded, so these are appended to the url
+			operation.ReservedQueryParams = append(operation.ReservedQueryParams, pythonParam)
+			operation.HasStyledParams = true
+		} else {
+			operation.QueryParams = append(operation.QueryParams, pythonParam)
+			operation.HasQueryParams = true
+		}
 		operation.Params = append(operation.Params, pythonParam)
-		operation.HasQueryParams = true
 	}
 
 	// Handle header parameters
@@ -368,6 +1024,15 @@ func (g *Generator) convertHandler(handler *parser.HttpHandler) *PythonOperation
 		operation.Params = append(operation.Params, pythonParam)
 	}
 
+	// Handle cookie parameters, these are sent together in a single Cookie header
+	for _, param := range handler.CookieParams {
+		pythonParam := g.convertParam(&param)
+		operation.CookieParams = append(operation.CookieParams, pythonParam)
+		operation.Params = append(operation.Params, pythonParam)
+	}
+
+	nonBodyParams = append(nonBodyParams, operation.Params...)
+
 	// Handle request body
 	if handler.RequestBody != nil {
 		operation.HasBody = true
@@ -375,29 +1040,74 @@ func (g *Generator) convertHandler(handler *parser.HttpHandler) *PythonOperation
 		case parser.ContentTypeFile:
 			operation.HasFileUpload = true
 			for _, field := range handler.RequestBody.Fields {
+				if field.ReadOnly {
+					continue
+				}
 				pythonParam := g.convertParam(&field)
-				if field.Type.PrimitiveKind == parser.PrimitiveBinary {
+				if pythonParam.Example == "" {
+					pythonParam.Example = requestExample(handler, field.Name)
+				}
+				pythonParam.MultipartKind = "form"
+				switch {
+				case isBinary(field.Type):
+					pythonParam.MultipartKind = "file"
 					pythonParam.Type = "FileTypes"
+				case field.Type.Kind == parser.TyKindArray && isBinary(field.Type.ElementType):
+					pythonParam.MultipartKind = "files"
+					pythonParam.Type = "List[FileTypes]"
+				}
+				if pythonParam.MultipartKind != "form" && !field.Required {
+					pythonParam.Type = fmt.Sprintf("Optional[%s]", pythonParam.Type)
 				}
 				operation.BodyParams = append(operation.BodyParams, pythonParam)
 				operation.Params = append(operation.Params, pythonParam)
 			}
 		case parser.ContentTypeJson:
 			for _, field := range handler.RequestBody.Fields {
+				if field.ReadOnly {
+					continue
+				}
 				pythonParam := g.convertParam(&field)
+				if pythonParam.Example == "" {
+					pythonParam.Example = requestExample(handler, field.Name)
+				}
 				operation.BodyParams = append(operation.BodyParams, pythonParam)
 				operation.Params = append(operation.Params, pythonParam)
 			}
 		default:
 			panic(fmt.Sprintf("unsupported content type %q", handler.ContentType))
 		}
+
+		if handler.ContentType == parser.ContentTypeJson && handler.RequestBody.IsNamed {
+			g.applyBodyStyle(operation, handler, nonBodyParams)
+		}
+	}
+
+	g.applyDeprecation(operation, handler)
+	for _, param := range signatureParams(operation) {
+		if param.Constraints != "" {
+			operation.ValidatedParams = append(operation.ValidatedParams, param)
+		}
 	}
 
 	// Handle response body using GetActualResponseBody
-	if actualBody := handler.GetActualResponseBody(); actualBody != nil {
-		operation.ResponseType = g.getFieldType(actualBody)
-	} else if handler.ResponseBody != nil {
-		operation.ResponseType = g.getFieldType(handler.ResponseBody)
+	g.request = false
+	operation.ResponseKind = string(handler.ResponseKind)
+	switch handler.ResponseKind {
+	case parser.ResponseKindBinary:
+		operation.IsRawResponse = true
+		operation.ResponseType = "BinaryResponse"
+		operation.AsyncResponseType = "AsyncBinaryResponse"
+	case parser.ResponseKindText:
+		operation.IsRawResponse = true
+		operation.ResponseType = "str"
+	default:
+		if actualBody := handler.GetActualResponseBody(); actualBody != nil {
+			operation.ResponseType = g.getFieldType(actualBody)
+		} else if handler.ResponseBody != nil {
+			operation.ResponseType = g.getFieldType(handler.ResponseBody)
+		}
+		operation.ResponseExamples = g.responseExamples(handler)
 	}
 
 	// Check if this is a paged operation using GetPageInfo
@@ -410,10 +1120,14 @@ func (g *Generator) convertHandler(handler *parser.HttpHandler) *PythonOperation
 		}
 		operation.ResponseType = fmt.Sprintf("NumberPaged[%s]", pageInfo.ItemType.Name)
 		operation.AsyncResponseType = fmt.Sprintf("AsyncNumberPaged[%s]", pageInfo.ItemType.Name)
-		operation.PageIndexName = g.toPythonVarName(pageInfo.PageIndexName)
-		operation.PageSizeName = g.toPythonVarName(pageInfo.PageSizeName)
+		operation.PageIndexName = g.toPythonParamName(pageInfo.PageIndexName)
+		operation.PageSizeName = g.toPythonParamName(pageInfo.PageSizeName)
 
 		for i, param := range operation.Params {
+			// Defaults of the spec win over the default pagination
+			if param.DefaultValue != "" && param.DefaultValue != "None" {
+				continue
+			}
 			if param.Name == operation.PageIndexName {
 				operation.Params[i].DefaultValue = "1"
 				operation.Params[i].Type = removeOptional(operation.Params[i].Type)
@@ -425,38 +1139,608 @@ func (g *Generator) convertHandler(handler *parser.HttpHandler) *PythonOperation
 		}
 	}
 
+	g.applyServers(operation, handler)
+	g.applyAuth(operation, handler)
+	if len(operation.AuthQueryParams) > 0 {
+		operation.HasQueryParams = true
+	}
+	if len(operation.CookieParams) > 0 || len(operation.AuthCookieParams) > 0 {
+		operation.HasCookies = true
+		operation.HasStyledParams = true
+	}
+
+	for _, param := range append(append([]PythonParam{}, operation.QueryParams...), headerParams...) {
+		if param.Serialize {
+			operation.HasStyledParams = true
+		}
+	}
+
 	// Update headers
-	if len(headerParams) > 0 || len(staticHeaders) > 0 {
+	if len(headerParams) > 0 || len(staticHeaders) > 0 || len(operation.AuthHeaders) > 0 || operation.HasCookies {
 		operation.HeaderParams = headerParams
 		operation.StaticHeaders = staticHeaders
 		operation.HasHeaders = true
 	}
 
+	operation.Example = g.exampleCall(operation)
+
 	return operation
 }
 
+// requestExample returns a Python literal of a field of the first request body example
+func requestExample(handler *parser.HttpHandler, name string) string {
+	if len(handler.RequestExamples) == 0 {
+		return ""
+	}
+	body, ok := handler.RequestExamples[0].Value.(map[string]any)
+	if !ok || body[name] == nil {
+		return ""
+	}
+	literal, _ := pythonLiteral(body[name])
+	return literal
+}
+
+// exampleCall renders a call of an operation with the examples of its parameters, required
+// parameters without example are left as ... It returns "" if no parameter has an example.
+func (g *Generator) exampleCall(operation *PythonOperation) string {
+	params := operation.Params
+	if operation.BodyStyle == string(parser.BodyStyleModel) {
+		params = operation.ModelParams
+	}
+
+	var args []string
+	hasExample := false
+	for _, param := range params {
+		switch {
+		case param.Example != "":
+			args = append(args, fmt.Sprintf("%s=%s", param.Name, param.Example))
+			hasExample = true
+		case param.IsModel && param.Name == operation.BodyParamName:
+			var fields []string
+			for _, bodyParam := range operation.BodyParams {
+				if bodyParam.Example != "" {
+					fields = append(fields, fmt.Sprintf("%s=%s", g.toPythonFieldName(bodyParam.JsonName), bodyParam.Example))
+				}
+			}
+			hasExample = hasExample || len(fields) > 0
+			args = append(args, fmt.Sprintf("%s=%s(%s)", param.Name, operation.BodyModel, strings.Join(fields, ", ")))
+		case !param.HasDefault && param.DefaultValue == "":
+			args = append(args, param.Name+"=...")
+		}
+	}
+	if !hasExample {
+		return ""
+	}
+	return fmt.Sprintf("coze.%s.%s(%s)", g.toPythonVarName(g.moduleName), operation.Name, strings.Join(args, ", "))
+}
+
+// responseExamples converts the response examples of a JSON operation. The requester returns
+// the data field of wrapped responses, so that is what examples are deserialized from.
+func (g *Generator) responseExamples(handler *parser.HttpHandler) []PythonResponseExample {
+	ty := handler.GetActualResponseBody()
+	if ty == nil {
+		ty = handler.ResponseBody
+	}
+	if ty == nil {
+		return nil
+	}
+
+	var examples []PythonResponseExample
+	for i, example := range handler.ResponseExamples {
+		value := example.Value
+		if ty != handler.ResponseBody {
+			body, ok := value.(map[string]any)
+			if !ok {
+				continue
+			}
+			value = body["data"]
+		}
+		payload, ok := pythonLiteral(value)
+		if !ok {
+			continue
+		}
+		examples = append(examples, PythonResponseExample{
+			ID:      fmt.Sprintf("%s:%s", handler.Name, util.Choose(example.Name != "", example.Name, strconv.Itoa(i))),
+			Cast:    g.getFieldType(ty),
+			Payload: payload,
+			Imports: namedTypes(ty),
+		})
+	}
+	return examples
+}
+
+// namedTypes returns the names of the classes a type annotation refers to
+func namedTypes(ty *parser.Ty) []string {
+	switch {
+	case ty == nil:
+		return nil
+	case ty.Kind == parser.TyKindArray:
+		return namedTypes(ty.ElementType)
+	case ty.Kind == parser.TyKindMap:
+		return namedTypes(ty.ValueType)
+	case ty.Kind == parser.TyKindObject && ty.IsNamed:
+		return []string{ty.Name}
+	default:
+		return nil
+	}
+}
+
+// applyBodyStyle fills the signatures used when the request body is passed as a single model
+func (g *Generator) applyBodyStyle(operation *PythonOperation, handler *parser.HttpHandler, nonBodyParams []PythonParam) {
+	if handler.BodyStyle != parser.BodyStyleModel && handler.BodyStyle != parser.BodyStyleBoth {
+		return
+	}
+
+	operation.BodyStyle = string(handler.BodyStyle)
+	operation.BodyModel = handler.RequestBody.Name
+	if g.isSplitModel(operation.BodyModel) {
+		operation.BodyModel += "Create"
+	} else {
+		var readOnly []string
+		for _, field := range handler.RequestBody.Fields {
+			if field.ReadOnly {
+				readOnly = append(readOnly, strconv.Quote(g.toPythonFieldName(field.Name)))
+			}
+		}
+		if len(readOnly) > 0 {
+			operation.BodyExclude = "{" + strings.Join(readOnly, ", ") + "}"
+		}
+	}
+	operation.BodyParamName = "request"
+	for _, param := range operation.Params {
+		if param.Name == operation.BodyParamName {
+			operation.BodyParamName = "request_body"
+			break
+		}
+	}
+
+	modelParam := PythonParam{
+		Name:        operation.BodyParamName,
+		Type:        operation.BodyModel,
+		Description: "The request body.",
+		IsModel:     true,
+	}
+	operation.ModelParams = append(append([]PythonParam{}, nonBodyParams...), modelParam)
+
+	if handler.BodyStyle == parser.BodyStyleBoth {
+		modelParam.Type = fmt.Sprintf("Optional[%s]", operation.BodyModel)
+		modelParam.DefaultValue = "None"
+		modelParam.HasDefault = true
+		operation.ImplParams = append(append([]PythonParam{}, nonBodyParams...), modelParam)
+		for _, param := range operation.BodyParams {
+			if !param.HasDefault {
+				param.Type = fmt.Sprintf("Optional[%s]", param.Type)
+				param.DefaultValue = "None"
+				param.HasDefault = true
+			}
+			operation.ImplParams = append(operation.ImplParams, param)
+		}
+	}
+}
+
+// applyServers points an operation at its own server when it overrides the document servers
+func (g *Generator) applyServers(operation *PythonOperation, handler *parser.HttpHandler) {
+	if len(handler.Servers) == 0 {
+		return
+	}
+
+	server := handler.Servers[0]
+	baseURL := server.DefaultURL()
+	if strings.HasPrefix(baseURL, "/") {
+		// relative servers are resolved against the client base url
+		operation.BaseURL = "{self._base_url}" + baseURL
+		return
+	}
+	operation.BaseURL = baseURL
+	if len(g.servers) == 0 {
+		return
+	}
+
+	// A server below the default one is a path below whichever server the client uses
+	defaultURL := g.servers[0].URL
+	if rest, ok := strings.CutPrefix(baseURL, defaultURL); ok && (rest == "" || strings.HasPrefix(rest, "/")) {
+		operation.BaseURL = "{self._base_url}" + rest
+		return
+	}
+
+	// Other servers are resolved for each environment from the values of the variables they
+	// share, which must be declared by the server. Custom base urls use the default server.
+	entries := make([]string, 0, len(g.servers))
+	differs := false
+	for _, environment := range g.servers {
+		values := make(map[string]string)
+		for _, variable := range server.Variables {
+			if value, ok := environment.Values[variable.Name]; ok && slices.Contains(variable.Enum, value) {
+				values[variable.Name] = value
+			}
+		}
+		serverURL := server.Resolve(values)
+		entries = append(entries, fmt.Sprintf("'%s': '%s'", environment.URL, serverURL))
+		differs = differs || serverURL != baseURL
+	}
+	if differs {
+		operation.BaseURL = fmt.Sprintf("{_server_url(self._base_url, {%s}, '%s')}", strings.Join(entries, ", "), baseURL)
+		operation.HasServerMap = true
+	}
+}
+
+// applyAuth fills the credentials an operation sends. The first security requirement with
+// schemes is used, and credentials are only sent when available if the operation can be
+// called anonymously.
+func (g *Generator) applyAuth(operation *PythonOperation, handler *parser.HttpHandler) {
+	operation.AuthOptional = handler.AllowsAnonymous()
+	if len(handler.Security) == 0 {
+		return
+	}
+
+	// The first requirement with credentials is used, an empty one only allows anonymous calls
+	var schemes []parser.SecurityRequirementScheme
+	for _, requirement := range handler.Security {
+		if len(requirement.Schemes) > 0 {
+			schemes = requirement.Schemes
+			break
+		}
+	}
+
+	for _, requirement := range schemes {
+		scheme := g.securitySchemes[requirement.Name]
+		if scheme == nil {
+			continue
+		}
+
+		switch {
+		case scheme.IsBearer():
+			// OAuth tokens are sent with the type they were issued with, e.g. Bearer
+			operation.AuthHeaders = append(operation.AuthHeaders, PythonAuthParam{
+				Name:  "Authorization",
+				Value: `f"{self._auth.token_type} {self._auth.token}"`,
+			})
+			if note := oauthNote(scheme, requirement.Scopes); note != "" {
+				operation.AuthNote = strings.TrimPrefix(operation.AuthNote+"\n    "+note, "\n    ")
+			}
+		case scheme.Type == parser.SecuritySchemeHttp:
+			operation.AuthHeaders = append(operation.AuthHeaders, PythonAuthParam{
+				Name:  "Authorization",
+				Value: fmt.Sprintf(`f"%s {self._auth.token}"`, strings.Title(scheme.Scheme)),
+			})
+		case scheme.Type == parser.SecuritySchemeApiKey && scheme.In == "query":
+			operation.AuthQueryParams = append(operation.AuthQueryParams, PythonAuthParam{
+				Name:  scheme.ParamName,
+				Value: "self._auth.token",
+			})
+		case scheme.Type == parser.SecuritySchemeApiKey && scheme.In == "cookie":
+			operation.AuthCookieParams = append(operation.AuthCookieParams, PythonAuthParam{
+				Name:  scheme.ParamName,
+				Value: "self._auth.token",
+			})
+		case scheme.Type == parser.SecuritySchemeApiKey:
+			operation.AuthHeaders = append(operation.AuthHeaders, PythonAuthParam{
+				Name:  scheme.ParamName,
+				Value: "self._auth.token",
+			})
+		}
+	}
+}
+
+// oauthNote documents the scopes of an OAuth2 or OpenID Connect requirement and where its
+// access tokens are issued, it is empty for other schemes
+func oauthNote(scheme *parser.SecurityScheme, scopes []string) string {
+	var sentences []string
+	if len(scopes) > 0 {
+		items := make([]string, 0, len(scopes))
+		for _, scope := range scopes {
+			item := "``" + scope + "``"
+			for _, flow := range scheme.Flows {
+				if description := flow.Scopes[scope]; description != "" {
+					item += " (" + description + ")"
+					break
+				}
+			}
+			items = append(items, item)
+		}
+		sentences = append(sentences, fmt.Sprintf("Requires the OAuth scopes %s.", strings.Join(items, ", ")))
+	}
+	switch scheme.Type {
+	case parser.SecuritySchemeOAuth2:
+		for _, flow := range scheme.Flows {
+			if flow.TokenURL != "" {
+				sentences = append(sentences, fmt.Sprintf("Access tokens are issued by %s.", flow.TokenURL))
+				break
+			}
+			if flow.AuthorizationURL != "" {
+				sentences = append(sentences, fmt.Sprintf("Access tokens are issued by authorizing at %s.", flow.AuthorizationURL))
+				break
+			}
+		}
+	case parser.SecuritySchemeOpenIdConnect:
+		if scheme.OpenIdConnectURL != "" {
+			sentences = append(sentences, fmt.Sprintf("Access tokens are issued by the provider of %s.", scheme.OpenIdConnectURL))
+		}
+	}
+	return strings.Join(sentences, "\n    ")
+}
+
+// applyDeprecation sets the deprecation message of an operation and collects the deprecated
+// parameters of its signature, which depends on the body style
+func (g *Generator) applyDeprecation(operation *PythonOperation, handler *parser.HttpHandler) {
+	operation.DeprecationMessage = deprecationMessage(operation.Name, handler.Deprecation)
+	for _, param := range signatureParams(operation) {
+		if param.DeprecationMessage != "" {
+			operation.DeprecatedParams = append(operation.DeprecatedParams, param)
+		}
+	}
+}
+
+// signatureParams returns the parameters in scope of the method body, which depend on the body style
+func signatureParams(operation *PythonOperation) []PythonParam {
+	switch operation.BodyStyle {
+	case string(parser.BodyStyleModel):
+		return operation.ModelParams
+	case string(parser.BodyStyleBoth):
+		return operation.ImplParams
+	default:
+		return operation.Params
+	}
+}
+
+// constraintArgs returns the pydantic Field keyword arguments of constraints, e.g. ge=1
+func constraintArgs(constraints *parser.Constraints, ty *parser.Ty) []string {
+	if constraints == nil {
+		return nil
+	}
+	formatFloat := func(v float64) string {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+
+	var args []string
+	if constraints.Minimum != nil {
+		args = append(args, util.Choose(constraints.ExclusiveMinimum, "gt=", "ge=")+formatFloat(*constraints.Minimum))
+	}
+	if constraints.Maximum != nil {
+		args = append(args, util.Choose(constraints.ExclusiveMaximum, "lt=", "le=")+formatFloat(*constraints.Maximum))
+	}
+	if constraints.MultipleOf != nil {
+		args = append(args, "multiple_of="+formatFloat(*constraints.MultipleOf))
+	}
+
+	// Lengths apply to strings and items to arrays, pydantic uses the same arguments for both.
+	// Formats which are not strings in Python, e.g. Int64String or datetime, have no length.
+	isString := ty != nil && ty.Kind == parser.TyKindPrimitive && pythonTypeMapping[ty.PrimitiveKind] == "str"
+	var minLength, maxLength *uint64
+	switch {
+	case isString:
+		minLength, maxLength = constraints.MinLength, constraints.MaxLength
+	case ty != nil && ty.Kind == parser.TyKindArray:
+		minLength, maxLength = constraints.MinItems, constraints.MaxItems
+	}
+	if minLength != nil {
+		args = append(args, fmt.Sprintf("min_length=%d", *minLength))
+	}
+	if maxLength != nil {
+		args = append(args, fmt.Sprintf("max_length=%d", *maxLength))
+	}
+	if constraints.Pattern != "" && isString {
+		args = append(args, "pattern="+strconv.Quote(constraints.Pattern))
+	}
+	return args
+}
+
+// deprecationMessage returns the warning of a deprecated operation, type or field, or an empty
+// string when it is not deprecated
+func deprecationMessage(name string, deprecation parser.Deprecation) string {
+	if !deprecation.Deprecated {
+		return ""
+	}
+	message := deprecation.DeprecatedMessage
+	if message == "" {
+		message = fmt.Sprintf("%s is deprecated.", name)
+	}
+	if deprecation.DeprecatedReplacement != "" {
+		message = fmt.Sprintf("%s Use %s instead.", strings.TrimSpace(message), deprecation.DeprecatedReplacement)
+	}
+	return message
+}
+
+// withDeprecationNote appends a deprecation note to a docstring
+func withDeprecationNote(description, message string) string {
+	if message == "" {
+		return description
+	}
+	if description == "" {
+		return ".. deprecated:: " + message
+	}
+	return description + "\n\n    .. deprecated:: " + message
+}
+
+// isBinary checks if a type is a binary primitive sent as a file part
+func isBinary(ty *parser.Ty) bool {
+	return ty != nil && ty.Kind == parser.TyKindPrimitive && ty.PrimitiveKind == parser.PrimitiveBinary
+}
+
 func (g *Generator) convertParam(field *parser.TyField) PythonParam {
-	fieldType := g.getFieldType(field.Type)
+	// Mutable defaults can not be used in signatures, these are left to the server
+	var specDefault string
+	var isFactory, hasSpecDefault bool
 	if !field.Required {
+		specDefault, isFactory, hasSpecDefault = g.pythonDefault(field.DefaultValue, field.Type)
+		hasSpecDefault = hasSpecDefault && !isFactory && specDefault != "None"
+	}
+
+	fieldType := g.getFieldType(field.Type)
+	if !field.Required && !hasSpecDefault {
 		fieldType = fmt.Sprintf("Optional[%s]", fieldType)
 	}
 
 	param := PythonParam{
-		Name:        g.toPythonVarName(field.Name),
-		JsonName:    field.Name,
-		Type:        fieldType,
-		Description: field.Description,
-		IsModel:     field.Type.IsNamed,
+		Name:               g.toPythonParamName(field.Name),
+		JsonName:           field.Name,
+		Type:               fieldType,
+		Description:        formatDocstring(g.description(field.Description, field.Descriptions), "        "),
+		IsModel:            field.Type.IsNamed,
+		DeprecationMessage: deprecationMessage(field.Name, field.Deprecation),
+		Constraints:        strings.Join(constraintArgs(field.Constraints, field.Type), ", "),
+	}
+	if len(field.Examples) > 0 {
+		param.Example, _ = pythonLiteral(field.Examples[0].Value)
+	}
+
+	param.Value = param.Name
+	if format := wireFormat(field.Type); format != "" {
+		param.Value = fmt.Sprintf("_format_value(%s, '%s')", param.Name, format)
 	}
 
 	if !field.Required {
-		param.DefaultValue = "None"
+		param.DefaultValue = util.Choose(hasSpecDefault, specDefault, "None")
 		param.HasDefault = true
 	}
 
+	if field.Style != "" {
+		param.Style = string(field.Style)
+		param.Explode = field.Explode
+		param.Serialize = needsSerialization(field)
+	}
+
 	return param
 }
 
+// pythonDefault renders the default of a schema as a Python literal of its type, enums of
+// generated enum classes as members. Lists and dicts are mutable and rendered as a default_factory instead, which is
+// reported by isFactory. ok is false if the default can not be rendered for the type.
+func (g *Generator) pythonDefault(value any, ty *parser.Ty) (literal string, isFactory bool, ok bool) {
+	if value == nil || ty == nil {
+		return "", false, false
+	}
+
+	switch ty.Kind {
+	case parser.TyKindPrimitive:
+		if len(ty.EnumValues) > 0 {
+			for _, enumValue := range ty.EnumValues {
+				if fmt.Sprint(enumValue.Val) != fmt.Sprint(value) {
+					continue
+				}
+				// Members can only be used where the enum class is generated and annotated
+				if ty.IsNamed && ty.Name != "" && g.getFieldType(ty) == ty.Name {
+					return fmt.Sprintf("%s.%s", ty.Name, g.enumMemberName(enumValue)), false, true
+				}
+				literal, ok = pythonLiteral(value)
+				return literal, false, ok
+			}
+			return "", false, false
+		}
+
+		switch ty.PrimitiveKind {
+		case parser.PrimitiveString, parser.PrimitiveURI:
+			if _, ok := value.(string); !ok {
+				return "", false, false
+			}
+		case parser.PrimitiveInt, parser.PrimitiveInt64:
+			if number, ok := value.(float64); !ok || number != float64(int64(number)) {
+				return "", false, false
+			}
+		case parser.PrimitiveFloat:
+			if _, ok := value.(float64); !ok {
+				return "", false, false
+			}
+		case parser.PrimitiveBool:
+			if _, ok := value.(bool); !ok {
+				return "", false, false
+			}
+		default:
+			// Formatted values are not validated by pydantic when they are defaults
+			return "", false, false
+		}
+		literal, ok = pythonLiteral(value)
+		return literal, false, ok
+
+	case parser.TyKindArray, parser.TyKindMap:
+		if literal, ok = pythonLiteral(value); !ok {
+			return "", false, false
+		}
+		switch literal {
+		case "[]":
+			return "list", true, true
+		case "{}":
+			return "dict", true, true
+		}
+		return "lambda: " + literal, true, true
+	}
+	return "", false, false
+}
+
+// pythonLiteral renders a value decoded from JSON as a Python literal
+func pythonLiteral(value any) (string, bool) {
+	switch v := value.(type) {
+	case nil:
+		return "None", true
+	case bool:
+		return util.Choose(v, "True", "False"), true
+	case string:
+		return strconv.Quote(v), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case int:
+		return strconv.Itoa(v), true
+	case []any:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			literal, ok := pythonLiteral(item)
+			if !ok {
+				return "", false
+			}
+			items = append(items, literal)
+		}
+		return "[" + strings.Join(items, ", ") + "]", true
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		items := make([]string, 0, len(v))
+		for _, key := range keys {
+			literal, ok := pythonLiteral(v[key])
+			if !ok {
+				return "", false
+			}
+			items = append(items, fmt.Sprintf("%s: %s", strconv.Quote(key), literal))
+		}
+		return "{" + strings.Join(items, ", ") + "}", true
+	default:
+		return "", false
+	}
+}
+
+// wireFormat returns the format of a parameter whose value is converted before being sent,
+// arrays are converted item by item
+func wireFormat(ty *parser.Ty) parser.PrimitiveKind {
+	if ty != nil && ty.Kind == parser.TyKindArray {
+		ty = ty.ElementType
+	}
+	if ty == nil || ty.Kind != parser.TyKindPrimitive || !pythonWireFormats[ty.PrimitiveKind] {
+		return ""
+	}
+	return ty.PrimitiveKind
+}
+
+// toPythonPath rewrites the placeholders of a path template into f-string expressions
+// percent-encoding the generated variables
+func (g *Generator) toPythonPath(path string, params map[string]PythonParam) string {
+	return parser.ReplacePathPlaceholders(path, func(name string) string {
+		param := params[name]
+		return fmt.Sprintf("{_encode_path('%s', %s, '%s', %s)}", name, param.Value, param.Style, util.Choose(param.Explode, "True", "False"))
+	})
+}
+
+// needsSerialization checks if a parameter value can not be passed as is, which is the case for
+// arrays and objects and for styles other than the default of the parameter location
+func needsSerialization(field *parser.TyField) bool {
+	switch field.Type.Kind {
+	case parser.TyKindArray, parser.TyKindMap, parser.TyKindObject:
+		return true
+	}
+	return field.Style != parser.ParamStyleForm && field.Style != parser.ParamStyleSimple
+}
+
 func (g *Generator) getFieldType(ty *parser.Ty) string {
 	if ty == nil {
 		return "Any"
@@ -464,6 +1748,9 @@ func (g *Generator) getFieldType(ty *parser.Ty) string {
 
 	switch ty.Kind {
 	case parser.TyKindPrimitive:
+		if _, ok := pythonFormatImports[ty.PrimitiveKind]; ok && g.formats != nil {
+			g.formats[ty.PrimitiveKind] = true
+		}
 		if pyType, ok := pythonTypeMapping[ty.PrimitiveKind]; ok {
 			return pyType
 		}
@@ -485,6 +1772,9 @@ func (g *Generator) getFieldType(ty *parser.Ty) string {
 
 	case parser.TyKindObject:
 		if ty.IsNamed {
+			if g.request && g.isSplitModel(ty.Name) {
+				return ty.Name + "Create"
+			}
 			return ty.Name
 		}
 		return "Dict[str, Any]"
@@ -494,21 +1784,52 @@ func (g *Generator) getFieldType(ty *parser.Ty) string {
 	}
 }
 
-func (g *Generator) formatDescription(desc string) string {
-	if desc == "" {
-		return desc
-	}
-	// Remove escape characters
-	desc = strings.ReplaceAll(desc, "\\", "")
-	// Convert consecutive newlines to single newline
-	desc = regexp.MustCompile(`\n\s*\n+`).ReplaceAllString(desc, "\n")
-	// Add indentation after each newline
-	desc = regexp.MustCompile(`\n`).ReplaceAllString(desc, "\n    ")
-	// Trim leading/trailing whitespace
-	desc = strings.TrimSpace(desc)
+// description returns the description in the documentation language, falling back to the
+// description picked by the parser when the spec has no text in that language
+func (g *Generator) description(desc string, descriptions parser.Descriptions) string {
+	switch g.DocLang {
+	case DocLangEn, DocLangZh:
+		if text := descriptions[g.DocLang]; text != "" {
+			return text
+		}
+	case DocLangBoth:
+		en, zh := descriptions[parser.LangEn], descriptions[parser.LangZh]
+		if en != "" && zh != "" {
+			return en + "\n\n" + zh
+		}
+	}
 	return desc
 }
 
+// docstringWidth is the width descriptions are reflowed to, leaving room for their indentation
+const docstringWidth = 80
+
+func (g *Generator) formatDescription(desc string) string {
+	return formatDocstring(desc, "    ")
+}
+
+// formatDocstring renders a Markdown description as docstring text whose lines after the first
+// are indented by indent. Backslashes and quotes that would end the docstring are escaped.
+func formatDocstring(desc, indent string) string {
+	if strings.TrimSpace(desc) == "" {
+		return ""
+	}
+	desc = markdown.ToRST(desc, docstringWidth)
+	desc = strings.ReplaceAll(desc, `\`, `\\`)
+	desc = strings.ReplaceAll(desc, `"""`, `\"\"\"`)
+	if strings.HasSuffix(desc, `"`) {
+		desc = strings.TrimSuffix(desc, `"`) + `\"`
+	}
+
+	lines := strings.Split(desc, "\n")
+	for i := 1; i < len(lines); i++ {
+		if lines[i] != "" {
+			lines[i] = indent + lines[i]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
 func (g *Generator) toPythonMethodName(name string) string {
 	// First check if there's a mapping in the module-specific config
 	if moduleConfig, ok := g.config.Modules[g.moduleName]; ok {
@@ -518,6 +1839,15 @@ func (g *Generator) toPythonMethodName(name string) string {
 	}
 
 	// If no mapping found, use the default conversion logic
+	return escapeName(ToPythonMethodName(name), reservedMethodNames)
+}
+
+func (g *Generator) toPythonVarName(name string) string {
+	return ToPythonVarName(name)
+}
+
+// ToPythonMethodName converts an operation id to a snake_case method name, e.g. ListBots -> list_bots
+func ToPythonMethodName(name string) string {
 	var result strings.Builder
 	for i, r := range name {
 		if i > 0 && r >= 'A' && r <= 'Z' {
@@ -528,7 +1858,8 @@ func (g *Generator) toPythonMethodName(name string) string {
 	return strings.ToLower(result.String())
 }
 
-func (g *Generator) toPythonVarName(name string) string {
+// ToPythonVarName converts a field or parameter name to a snake_case identifier
+func ToPythonVarName(name string) string {
 	// Replace any non-alphanumeric characters with underscore
 	reg := regexp.MustCompile(`[^a-zA-Z0-9]+`)
 	name = reg.ReplaceAllString(name, "_")
@@ -601,9 +1932,9 @@ func (g *Generator) toEnumName(name string) string {
 	return strings.ToUpper(strings.Trim(name, "_"))
 }
 
-func (g *Generator) getTemplate() string {
+func (g *Generator) getTemplate(name string) string {
 	// Read template from embedded file
-	templateContent, err := fs.ReadFile(templateFS, "templates/sdk.tmpl")
+	templateContent, err := fs.ReadFile(templateFS, name)
 	if err != nil {
 		return ""
 	}
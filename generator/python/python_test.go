@@ -0,0 +1,823 @@
+package python
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/coze-dev/coze-sdk-gen/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertType_Aliases(t *testing.T) {
+	g := &Generator{}
+	class := g.convertType(&parser.Ty{
+		Name:    "Message",
+		Kind:    parser.TyKindObject,
+		IsNamed: true,
+		Fields: []parser.TyField{
+			{Name: "from", Type: stringTy(), Required: true},
+			{Name: "botId", Type: stringTy()},
+			{Name: "content", Type: stringTy()},
+		},
+	}, false)
+	require.True(t, class.HasAliases)
+
+	args := make(map[string][]string)
+	for _, field := range class.Fields {
+		args[field.Name] = field.FieldArgs
+	}
+	require.Equal(t, map[string][]string{
+		"from_":   {"...", `alias="from"`},
+		"bot_id":  {"None", `alias="botId"`},
+		"content": nil,
+	}, args)
+}
+
+func TestConvertModule_Exports(t *testing.T) {
+	g := &Generator{}
+	module := g.convertModule(&parser.Module{
+		Name: "bots.versions",
+		Types: []*parser.Ty{
+			{Name: "BotVersion", Kind: parser.TyKindObject, IsNamed: true, Fields: []parser.TyField{{Name: "id", Type: stringTy()}}},
+			{Name: "_PrivateListBotVersionsData", Kind: parser.TyKindObject, IsNamed: true},
+			{Name: "BotStatus", Kind: parser.TyKindPrimitive, IsNamed: true, EnumValues: []parser.TyEnumValue{{Val: 1}}},
+		},
+	})
+	require.Equal(t, []string{"BotVersion", "BotStatus", "BotsVersionsClient", "AsyncBotsVersionsClient"}, module.Exports)
+}
+
+func TestGeneratePyproject(t *testing.T) {
+	g := &Generator{Package: Package{Name: "coze-bots"}}
+	pyproject, err := g.generatePyproject([]string{"bots", "bots.versions", "workflows.runs", "tests"})
+	require.NoError(t, err)
+	require.Contains(t, pyproject, `version = "0.1.0"`)
+	require.Contains(t, pyproject, `package-dir = { "coze_bots" = "." }`)
+
+	_, packages, _ := strings.Cut(pyproject, "packages = [")
+	packages, _, _ = strings.Cut(packages, "]")
+	require.Equal(t, []string{
+		"coze_bots",
+		"coze_bots.bots",
+		"coze_bots.bots.versions",
+		"coze_bots.tests",
+		"coze_bots.workflows",
+		"coze_bots.workflows.runs",
+	}, strings.Fields(strings.NewReplacer(`"`, "", ",", "").Replace(packages)))
+}
+
+// baseSpec declares the modules and types the parser configuration of the generator refers to
+const baseSpec = `
+openapi: 3.0.3
+info:
+  title: base
+  version: "1"
+paths:
+  /v1/files/retrieve:
+    get:
+      operationId: RetrieveFileOpen
+      tags:
+        - files
+      parameters:
+        - name: file_id
+          in: query
+          required: true
+          schema:
+            type: string
+      responses:
+        "200":
+          description: ""
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/File"
+components:
+  schemas:
+    File:
+      type: object
+      properties:
+        id:
+          type: string
+    Bot:
+      type: object
+      properties:
+        bot_id:
+          type: string
+    SpacePublishedBotsInfo:
+      type: object
+      properties:
+        total:
+          type: integer
+`
+
+// generate generates the SDK of a spec and returns the generated files, which are compiled
+// when python3 is installed
+func generate(t *testing.T, spec string) map[string]string {
+	t.Helper()
+	g := &Generator{}
+	files, err := g.Generate(context.Background(), []parser.Spec{
+		{Path: "openapi.yaml", Content: []byte(spec)},
+		{Path: "base.yaml", Content: []byte(baseSpec)},
+	})
+	require.NoError(t, err)
+
+	python, err := exec.LookPath("python3")
+	if err != nil {
+		return files
+	}
+	dir := t.TempDir()
+	for file, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(file))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	output, err := exec.Command(python, "-m", "compileall", "-q", dir).CombinedOutput()
+	require.NoError(t, err, string(output))
+	return files
+}
+
+func TestGenerate_Defaults(t *testing.T) {
+	files := generate(t, `
+openapi: 3.0.3
+info:
+  title: test
+  version: "1"
+paths:
+  /v1/robots:
+    get:
+      operationId: ListRobots
+      tags:
+        - robots
+      parameters:
+        - name: kind
+          in: query
+          schema:
+            type: string
+            enum: [a, b]
+            default: a
+        - name: page_size
+          in: query
+          schema:
+            type: integer
+            default: 20
+      responses:
+        "200":
+          description: ""
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Robot"
+components:
+  schemas:
+    Robot:
+      type: object
+      properties:
+        mode:
+          type: string
+          enum: [x, y]
+          default: x
+        status:
+          $ref: "#/components/schemas/RobotStatus"
+        tags:
+          type: array
+          items:
+            type: string
+          default: []
+    RobotStatus:
+      type: integer
+      enum: [0, 1]
+      default: 1
+`)
+	robots := files["robots/__init__.py"]
+	require.Contains(t, robots, "    mode: str  = \"x\"\n")
+	require.Contains(t, robots, "    status: int  = 1\n")
+	require.Contains(t, robots, "    tags: List[str]  = Field(default_factory=list)\n")
+	require.Contains(t, robots, "        kind: str  = \"a\",\n")
+	require.Contains(t, robots, "        page_size: int  = 20,\n")
+}
+
+func TestGenerate_BodyStyles(t *testing.T) {
+	files := generate(t, `
+openapi: 3.0.3
+info:
+  title: test
+  version: "1"
+paths:
+  /v1/robots:
+    post:
+      operationId: CreateRobot
+      tags:
+        - robots
+      x-coze-body-style: both
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: "#/components/schemas/CreateRobotReq"
+      responses:
+        "200":
+          description: ""
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Robot"
+    put:
+      operationId: UpdateRobot
+      tags:
+        - robots
+      x-coze-body-style: model
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: "#/components/schemas/CreateRobotReq"
+      responses:
+        "200":
+          description: ""
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Robot"
+components:
+  schemas:
+    CreateRobotReq:
+      type: object
+      x-coze-order: [name, icon]
+      required:
+        - name
+      properties:
+        name:
+          type: string
+        icon:
+          $ref: "#/components/schemas/Icon"
+    Icon:
+      type: object
+      properties:
+        url:
+          type: string
+    Robot:
+      type: object
+      properties:
+        id:
+          type: string
+`)
+	robots := files["robots/__init__.py"]
+	// Overloads of the model and the keyword arguments, the implementation accepts both
+	require.Contains(t, robots, "    @overload\n    def create_robot(\n        self,\n        *,\n        request: CreateRobotReq ,\n")
+	require.Contains(t, robots, "    @overload\n    def create_robot(\n        self,\n        *,\n        name: str ,\n        icon: Optional[Icon]  = None,\n")
+	require.Contains(t, robots, "        request: Optional[CreateRobotReq]  = None,\n        name: Optional[str]  = None,\n        icon: Optional[Icon]  = None,\n")
+	require.Contains(t, robots, `"name": _dump_value(name),"icon": _dump_value(icon),`)
+	require.Contains(t, robots, "    def update_robot(\n        self,\n        *,\n        request: CreateRobotReq ,\n")
+	require.Contains(t, robots, `body = request.model_dump(mode="json", by_alias=True, exclude_none=True)`)
+}
+
+func TestGenerate_Multipart(t *testing.T) {
+	files := generate(t, `
+openapi: 3.0.3
+info:
+  title: test
+  version: "1"
+paths:
+  /v1/robots/avatar:
+    post:
+      operationId: UploadAvatar
+      tags:
+        - robots
+      requestBody:
+        content:
+          multipart/form-data:
+            schema:
+              type: object
+              required:
+                - images
+              properties:
+                images:
+                  type: array
+                  items:
+                    type: string
+                    format: binary
+                cover:
+                  type: string
+                  format: binary
+                caption:
+                  type: string
+      responses:
+        "200":
+          description: ""
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  code:
+                    type: integer
+`)
+	robots := files["robots/__init__.py"]
+	require.Contains(t, robots, "        images: List[FileTypes] ,\n")
+	require.Contains(t, robots, "        cover: Optional[FileTypes]  = None,\n")
+	require.Contains(t, robots, `*_multipart_files("images", images),`)
+	require.Contains(t, robots, `*_multipart_file("cover", cover),`)
+	require.Contains(t, robots, `*_multipart_form("caption", caption),`)
+	require.Contains(t, robots, "files=multipart,")
+}
+
+func TestGenerate_BinaryResponses(t *testing.T) {
+	files := generate(t, `
+openapi: 3.0.3
+info:
+  title: test
+  version: "1"
+paths:
+  /v1/robots/speech:
+    get:
+      operationId: GetSpeech
+      tags:
+        - robots
+      responses:
+        "200":
+          description: ""
+          content:
+            audio/mpeg:
+              schema:
+                type: string
+                format: binary
+`)
+	robots := files["robots/__init__.py"]
+	require.Contains(t, robots, "    ) -> BinaryResponse:\n")
+	require.Contains(t, robots, "        return BinaryResponse(response)\n")
+	require.Contains(t, robots, "    ) -> AsyncBinaryResponse:\n")
+	require.Contains(t, robots, "        return AsyncBinaryResponse(response)\n")
+}
+
+func TestGenerate_Params(t *testing.T) {
+	files := generate(t, `
+openapi: 3.0.3
+info:
+  title: test
+  version: "1"
+paths:
+  /v1/robots/{robotId}/messages:
+    get:
+      operationId: ListMessages
+      tags:
+        - robots
+      parameters:
+        - name: robotId
+          in: path
+          required: true
+          schema:
+            type: string
+        - name: ids
+          in: query
+          style: form
+          explode: false
+          schema:
+            type: array
+            items:
+              type: string
+        - name: filter
+          in: query
+          style: deepObject
+          explode: true
+          schema:
+            type: object
+            properties:
+              name:
+                type: string
+        - name: page_size
+          in: query
+          schema:
+            type: integer
+            minimum: 1
+            maximum: 50
+        - name: session
+          in: cookie
+          schema:
+            type: string
+      responses:
+        "200":
+          description: ""
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  code:
+                    type: integer
+`)
+	robots := files["robots/__init__.py"]
+	require.Contains(t, robots, "        robot_id: str ,\n        ids: Optional[List[str]]  = None,\n        filter_: Optional[Dict[str, Any]]  = None,\n        page_size: Optional[int]  = None,\n        session: Optional[str]  = None,\n")
+	require.Contains(t, robots, `url = f"{self._base_url}/v1/robots/{_encode_path('robotId', robot_id, 'simple', False)}/messages"`)
+	require.Contains(t, robots, `**_serialize_query("ids", ids, "form", False),**_serialize_query("filter", filter_, "deepObject", True),"page_size": page_size,`)
+	require.Contains(t, robots, `_validate("page_size", page_size, ge=1, le=50)`)
+	// The Cookie header is left out when no cookie is set
+	require.Contains(t, robots, `**_serialize_cookies({"session": session,}),`)
+}
+
+func TestGenerate_Fields(t *testing.T) {
+	files := generate(t, `
+openapi: 3.0.3
+info:
+  title: test
+  version: "1"
+paths:
+  /v1/robots:
+    get:
+      operationId: GetRobot
+      tags:
+        - robots
+      parameters:
+        - name: robot_id
+          in: query
+          schema:
+            type: string
+            format: int64
+            pattern: "^\\d+$"
+        - name: since
+          in: query
+          schema:
+            type: string
+            format: date-time
+      responses:
+        "200":
+          description: ""
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Robot"
+components:
+  schemas:
+    Robot:
+      type: object
+      required:
+        - id
+        - name
+      properties:
+        id:
+          type: string
+          format: int64
+          pattern: "^\\d+$"
+          maxLength: 19
+        name:
+          type: string
+          minLength: 1
+          maxLength: 20
+        created_at:
+          type: integer
+          x-coze-format: unix-timestamp
+        avatar:
+          type: string
+          format: byte
+        uuid:
+          type: string
+          format: uuid
+        score:
+          type: integer
+          minimum: 0
+`)
+	robots := files["robots/__init__.py"]
+	require.Contains(t, robots, "    id_: Int64String  = Field(..., alias=\"id\")\n")
+	require.Contains(t, robots, "    name: str  = Field(..., min_length=1, max_length=20)\n")
+	require.Contains(t, robots, "    created_at: Optional[UnixTimestamp]  = None\n")
+	require.Contains(t, robots, "    avatar: Optional[Base64Bytes]  = None\n")
+	require.Contains(t, robots, "    uuid: Optional[UUID]  = None\n")
+	require.Contains(t, robots, "    score: Optional[int]  = Field(None, ge=0)\n")
+	require.Contains(t, robots, "        since: Optional[datetime]  = None,\n")
+	// Lengths and patterns do not apply to formats which are not strings in Python
+	require.NotContains(t, robots, `_validate("robot_id"`)
+	require.Contains(t, robots, `"since": _format_value(since, 'date-time'),`)
+	require.Contains(t, robots, "Int64String = Annotated[int, PlainSerializer(str, return_type=str)]\n")
+}
+
+func TestGenerate_ReadWriteOnly(t *testing.T) {
+	files := generate(t, `
+openapi: 3.0.3
+info:
+  title: test
+  version: "1"
+paths:
+  /v1/robots:
+    get:
+      operationId: GetRobot
+      tags:
+        - robots
+      responses:
+        "200":
+          description: ""
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Robot"
+    put:
+      operationId: UpdateRobot
+      tags:
+        - robots
+      x-coze-body-style: model
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: "#/components/schemas/RobotConfig"
+      responses:
+        "200":
+          description: ""
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/RobotConfig"
+components:
+  schemas:
+    Robot:
+      type: object
+      x-coze-order: [robot_id, password]
+      required:
+        - robot_id
+        - password
+      properties:
+        robot_id:
+          type: string
+          readOnly: true
+        password:
+          type: string
+          writeOnly: true
+    RobotConfig:
+      type: object
+      x-coze-order: [robot_id, password]
+      required:
+        - robot_id
+        - password
+      properties:
+        robot_id:
+          type: string
+          readOnly: true
+        password:
+          type: string
+          writeOnly: true
+`)
+	robots := files["robots/__init__.py"]
+	// Robot is only received, the server never sends its writeOnly fields
+	require.Contains(t, robots, "class Robot(CozeModel):\n    robot_id: str \n    \n")
+	// RobotConfig is sent and received, neither side can require its fields
+	require.Contains(t, robots, "class RobotConfig(CozeModel):\n    robot_id: Optional[str]  = None\n    password: Optional[str]  = None\n")
+}
+
+func TestGenerate_Deprecation(t *testing.T) {
+	files := generate(t, `
+openapi: 3.0.3
+info:
+  title: test
+  version: "1"
+paths:
+  /v1/robots:
+    get:
+      operationId: GetRobot
+      tags:
+        - robots
+      responses:
+        "200":
+          description: ""
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Robot"
+components:
+  schemas:
+    Robot:
+      type: object
+      deprecated: true
+      x-coze-deprecated-replacement: Agent
+      properties:
+        name:
+          type: string
+`)
+	robots := files["robots/__init__.py"]
+	require.Contains(t, robots, "from typing_extensions import deprecated\n")
+	require.Contains(t, robots, "\"\"\".. deprecated:: Robot is deprecated. Use Agent instead.\"\"\"\n@deprecated(\"Robot is deprecated. Use Agent instead.\")\nclass Robot(CozeModel):\n")
+}
+
+func TestGenerate_Examples(t *testing.T) {
+	files := generate(t, `
+openapi: 3.0.3
+info:
+  title: test
+  version: "1"
+paths:
+  /v1/robots:
+    get:
+      operationId: GetRobot
+      tags:
+        - robots
+      parameters:
+        - name: robot_id
+          in: query
+          required: true
+          schema:
+            type: string
+          example: "7351"
+      responses:
+        "200":
+          description: ""
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Robot"
+              example:
+                id: "7351"
+                name: robot
+components:
+  schemas:
+    Robot:
+      type: object
+      properties:
+        id:
+          type: string
+        name:
+          type: string
+`)
+	require.Contains(t, files["robots/__init__.py"], "        coze.robots.get_robot(robot_id=\"7351\")\n")
+	require.Contains(t, files["tests/test_robots_examples.py"], `pytest.param(Robot, {"id": "7351", "name": "robot"}, id="GetRobot:0"),`)
+	require.Contains(t, files["tests/test_robots_examples.py"], "from ..robots import Robot\n")
+}
+
+func TestGenerate_Client(t *testing.T) {
+	files := generate(t, `
+openapi: 3.0.3
+info:
+  title: test
+  version: "1"
+servers:
+  - url: https://api.coze.com
+paths:
+  /v1/robots:
+    get:
+      operationId: GetRobot
+      tags:
+        - robots
+      responses:
+        "200":
+          description: ""
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  code:
+                    type: integer
+`)
+	client := files["__init__.py"]
+	require.Contains(t, client, "from .robots import RobotsClient, AsyncRobotsClient\n")
+	require.Contains(t, client, "        self.robots = RobotsClient(self._base_url, self._auth, self._requester)\n")
+	// The requester must not authorize requests itself, operations without security send no credentials
+	require.Contains(t, client, "        self._requester = requester or Requester()\n")
+	require.NotContains(t, client, "Requester(auth=")
+}
+
+func TestGenerate_Auth(t *testing.T) {
+	files := generate(t, `
+openapi: 3.0.3
+info:
+  title: test
+  version: "1"
+security:
+  - oauth: [bot.read]
+paths:
+  /v1/robots:
+    get:
+      operationId: GetRobot
+      tags:
+        - robots
+      responses:
+        "200":
+          description: ""
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  code:
+                    type: integer
+    post:
+      operationId: CreateRobot
+      tags:
+        - robots
+      security:
+        - basic: []
+          key: []
+      responses:
+        "200":
+          description: ""
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  code:
+                    type: integer
+    delete:
+      operationId: DeleteRobot
+      tags:
+        - robots
+      security: []
+      responses:
+        "200":
+          description: ""
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  code:
+                    type: integer
+components:
+  securitySchemes:
+    oauth:
+      type: oauth2
+      flows:
+        clientCredentials:
+          tokenUrl: https://api.coze.com/api/permission/oauth2/token
+          scopes:
+            bot.read: Read bots
+    basic:
+      type: http
+      scheme: basic
+    key:
+      type: apiKey
+      in: query
+      name: api_key
+`)
+	robots := files["robots/__init__.py"]
+	require.Contains(t, robots, `"Authorization": f"{self._auth.token_type} {self._auth.token}",`)
+	require.Contains(t, robots, "    Requires the OAuth scopes ``bot.read`` (Read bots).\n    Access tokens are issued by https://api.coze.com/api/permission/oauth2/token.\n")
+	require.Contains(t, robots, `"Authorization": f"Basic {self._auth.token}",`)
+	require.Contains(t, robots, `"api_key": self._auth.token,`)
+
+	// Anonymous operations send no credentials
+	_, deleteRobot, _ := strings.Cut(robots, "    def delete_robot(")
+	deleteRobot, _, _ = strings.Cut(deleteRobot, "    def ")
+	require.NotContains(t, deleteRobot, "self._auth")
+}
+
+func TestGenerate_Servers(t *testing.T) {
+	files := generate(t, `
+openapi: 3.0.3
+info:
+  title: test
+  version: "1"
+servers:
+  - url: https://api.coze.{region}
+    x-coze-name: coze
+    variables:
+      region:
+        default: com
+        enum: [com, cn]
+paths:
+  /v1/robots/publish:
+    post:
+      operationId: PublishRobot
+      tags:
+        - robots
+      servers:
+        - url: https://apps.coze.{region}
+          variables:
+            region:
+              default: com
+              enum: [com, cn]
+      responses:
+        "200":
+          description: ""
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  code:
+                    type: integer
+  /v1/robots/upload:
+    post:
+      operationId: UploadRobot
+      tags:
+        - robots
+      servers:
+        - url: https://upload.coze.com
+      responses:
+        "200":
+          description: ""
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  code:
+                    type: integer
+`)
+	require.Contains(t, files["__init__.py"], "COZE_COM_BASE_URL = \"https://api.coze.com\"\nCOZE_CN_BASE_URL = \"https://api.coze.cn\"\n")
+
+	robots := files["robots/__init__.py"]
+	require.Contains(t, robots, `url = f"{_server_url(self._base_url, {'https://api.coze.com': 'https://apps.coze.com', 'https://api.coze.cn': 'https://apps.coze.cn'}, 'https://apps.coze.com')}/v1/robots/publish"`)
+	// Servers without variables have no declared counterpart in other environments
+	require.Contains(t, robots, `url = f"https://upload.coze.com/v1/robots/upload"`)
+	require.NotContains(t, robots, "upload.coze.cn")
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/coze-dev/coze-sdk-gen/lint"
+	"github.com/spf13/cobra"
+)
+
+var lintFormat string
+
+func init() {
+	lintCmd.Flags().StringVarP(&lintFormat, "format", "f", string(lint.FormatText), "Output format: text, json or sarif")
+	rootCmd.AddCommand(lintCmd)
+}
+
+var lintCmd = &cobra.Command{
+	Use:   "lint <openapi.yaml|dir>...",
+	Short: "Report spec problems that make the generated SDK worse",
+	Long: `Checks OpenAPI specifications for problems affecting SDK generation, such as missing
+operation ids, unordered objects, unnamed enums, inline objects and name collisions.
+Exits with a non-zero status when errors are found.`,
+	Args:         cobra.MinimumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		specs, err := readSpecs(args)
+		if err != nil {
+			return err
+		}
+
+		// The specs are merged into one SDK, so they are linted together
+		findings, err := lint.Lint(specs...)
+		if err != nil {
+			return err
+		}
+
+		if err := lint.Write(os.Stdout, lint.Format(lintFormat), findings); err != nil {
+			return err
+		}
+		if lint.HasErrors(findings) {
+			return fmt.Errorf("lint found errors")
+		}
+		return nil
+	},
+}
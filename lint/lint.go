@@ -0,0 +1,510 @@
+package lint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/coze-dev/coze-sdk-gen/generator/python"
+	"github.com/coze-dev/coze-sdk-gen/parser"
+	"github.com/getkin/kin-openapi/openapi3"
+	"gopkg.in/yaml.v3"
+)
+
+// Severity represents how bad a finding is
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Rule is a check performed on a spec
+type Rule struct {
+	ID          string
+	Severity    Severity
+	Description string
+}
+
+// Rules checked by the linter
+var (
+	RuleMissingOperationID   = Rule{"missing-operation-id", SeverityError, "Operations must have an operationId, it names the SDK method"}
+	RuleDuplicateOperationID = Rule{"duplicate-operation-id", SeverityError, "Operation ids must be unique"}
+	RuleMissingTags          = Rule{"missing-tags", SeverityWarning, "Operations without tags are generated into the default module"}
+	RuleMissingOrder         = Rule{"missing-order", SeverityWarning, "Object schemas need x-coze-order listing every property for a stable field order"}
+	RuleMissingEnumNames     = Rule{"missing-enum-names", SeverityWarning, "Enums need x-coze-enum-names with one name per value"}
+	RuleInlineObject         = Rule{"inline-object", SeverityWarning, "Inline object schemas are generated as Dict[str, Any]"}
+	RuleNameCollision        = Rule{"name-collision", SeverityError, "Names must stay unique after conversion to Python identifiers"}
+	RuleChineseDescription   = Rule{"chinese-description", SeverityWarning, "Descriptions should be in English when the title is"}
+	RuleUnusedSchema         = Rule{"unused-schema", SeverityWarning, "Component schemas should be used by an operation"}
+)
+
+// Rules returns all rules checked by the linter
+func Rules() []Rule {
+	return []Rule{
+		RuleMissingOperationID,
+		RuleDuplicateOperationID,
+		RuleMissingTags,
+		RuleMissingOrder,
+		RuleMissingEnumNames,
+		RuleInlineObject,
+		RuleNameCollision,
+		RuleChineseDescription,
+		RuleUnusedSchema,
+	}
+}
+
+// Finding is a problem found in a spec
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	File     string   `json:"file"`
+	Pointer  string   `json:"pointer"` // JSON pointer to the offending node
+	Line     int      `json:"line,omitempty"`
+}
+
+// linter collects the findings of a single spec
+type linter struct {
+	spec       parser.Spec
+	doc        *openapi3.T
+	root       *yaml.Node
+	operations *operations
+	findings   []Finding
+}
+
+// operations are the operation ids and method names of all specs, as the specs are merged into one SDK
+type operations struct {
+	ids     map[string]string            // operation id -> method, path and file declaring it
+	methods map[string]map[string]string // module -> method name -> operation id
+}
+
+// Lint checks specs for problems that make the generated SDK worse. The specs are checked together,
+// operation ids and method names must be unique across all of them.
+func Lint(specs ...parser.Spec) ([]Finding, error) {
+	ops := &operations{ids: make(map[string]string), methods: make(map[string]map[string]string)}
+	var findings []Finding
+	for _, spec := range specs {
+		specFindings, err := lintSpec(spec, ops)
+		if err != nil {
+			return nil, fmt.Errorf("failed to lint %s: %w", spec.Path, err)
+		}
+		findings = append(findings, specFindings...)
+	}
+	return findings, nil
+}
+
+// lintSpec checks a single spec, with the operations of the specs checked before it
+func lintSpec(spec parser.Spec, ops *operations) ([]Finding, error) {
+	doc, err := parser.LoadSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(spec.Content, &node); err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI document: %w", err)
+	}
+
+	l := &linter{spec: spec, doc: doc, operations: ops}
+	if len(node.Content) > 0 {
+		l.root = node.Content[0]
+	}
+
+	l.checkOperations()
+	l.checkSchemas()
+	l.checkUnusedSchemas()
+
+	sort.SliceStable(l.findings, func(i, j int) bool {
+		return l.findings[i].Line < l.findings[j].Line
+	})
+	return l.findings, nil
+}
+
+// report adds a finding at a JSON pointer
+func (l *linter) report(rule Rule, pointer string, format string, args ...any) {
+	l.findings = append(l.findings, Finding{
+		Rule:     rule.ID,
+		Severity: rule.Severity,
+		Message:  fmt.Sprintf(format, args...),
+		File:     l.spec.Path,
+		Pointer:  pointer,
+		Line:     lineOf(l.root, pointer),
+	})
+}
+
+// checkOperations checks operation ids, tags, method and parameter names and descriptions
+func (l *linter) checkOperations() {
+	operationIDs := l.operations.ids
+	methodNames := l.operations.methods
+	for _, path := range sortedKeys(l.doc.Paths.Map()) {
+		pathItem := l.doc.Paths.Value(path)
+		operations := pathItem.Operations()
+		methods := make([]string, 0, len(operations))
+		for method := range operations {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+
+		for _, method := range methods {
+			op := operations[method]
+			pointer := "/paths/" + escape(path) + "/" + strings.ToLower(method)
+
+			if op.OperationID == "" {
+				l.report(RuleMissingOperationID, pointer, "%s %s has no operationId", method, path)
+			} else if existing, ok := operationIDs[op.OperationID]; ok {
+				l.report(RuleDuplicateOperationID, pointer, "operationId %s is also used by %s", op.OperationID, existing)
+			} else {
+				operationIDs[op.OperationID] = method + " " + path + " in " + l.spec.Path
+			}
+
+			moduleName := "default"
+			if len(op.Tags) == 0 {
+				l.report(RuleMissingTags, pointer, "%s %s has no tags", method, path)
+			} else {
+				moduleName = strings.Join(op.Tags, ".")
+			}
+			if op.OperationID != "" {
+				if methodNames[moduleName] == nil {
+					methodNames[moduleName] = make(map[string]string)
+				}
+				name := python.ToPythonMethodName(op.OperationID)
+				if existing, ok := methodNames[moduleName][name]; ok && existing != op.OperationID {
+					l.report(RuleNameCollision, pointer, "operationId %s and %s are both generated as method %s", existing, op.OperationID, name)
+				} else {
+					methodNames[moduleName][name] = op.OperationID
+				}
+			}
+
+			l.checkDescription(pointer, op.Summary, op.Description)
+			l.checkParameters(pointer, pathItem, op)
+			l.checkOperationSchemas(pointer, op)
+		}
+	}
+}
+
+// checkParameters checks that parameters and flattened body fields have distinct Python names
+func (l *linter) checkParameters(pointer string, pathItem *openapi3.PathItem, op *openapi3.Operation) {
+	names := make(map[string]string)
+	check := func(name string) {
+		varName := python.ToPythonVarName(name)
+		if existing, ok := names[varName]; ok && existing != name {
+			l.report(RuleNameCollision, pointer, "parameters %s and %s are both generated as %s", existing, name, varName)
+			return
+		}
+		names[varName] = name
+	}
+
+	for _, params := range []openapi3.Parameters{pathItem.Parameters, op.Parameters} {
+		for _, param := range params {
+			if param != nil && param.Value != nil {
+				check(param.Value.Name)
+			}
+		}
+	}
+	if op.RequestBody != nil && op.RequestBody.Value != nil {
+		for _, mediaType := range op.RequestBody.Value.Content {
+			if mediaType.Schema == nil || mediaType.Schema.Value == nil {
+				continue
+			}
+			for _, name := range sortedKeys(mediaType.Schema.Value.Properties) {
+				check(name)
+			}
+		}
+	}
+}
+
+// checkOperationSchemas checks the inline schemas of parameters, request bodies and responses
+func (l *linter) checkOperationSchemas(pointer string, op *openapi3.Operation) {
+	for i, param := range op.Parameters {
+		if param != nil && param.Ref == "" && param.Value != nil && param.Value.Schema != nil {
+			l.walkSchema(fmt.Sprintf("%s/parameters/%d/schema", pointer, i), param.Value.Schema, true)
+		}
+	}
+	if op.RequestBody != nil && op.RequestBody.Ref == "" && op.RequestBody.Value != nil {
+		l.walkContent(pointer+"/requestBody/content", op.RequestBody.Value.Content)
+	}
+	if op.Responses != nil {
+		for _, status := range sortedKeys(op.Responses.Map()) {
+			response := op.Responses.Value(status)
+			if response != nil && response.Ref == "" && response.Value != nil {
+				l.walkContent(pointer+"/responses/"+escape(status)+"/content", response.Value.Content)
+			}
+		}
+	}
+}
+
+// walkContent checks the schemas of a content map, these are named by the parser
+func (l *linter) walkContent(pointer string, content openapi3.Content) {
+	for _, contentType := range sortedKeys(content) {
+		if schema := content[contentType].Schema; schema != nil {
+			l.walkSchema(pointer+"/"+escape(contentType)+"/schema", schema, false)
+		}
+	}
+}
+
+// checkSchemas checks the component schemas, including those of shared parameters, bodies and responses
+func (l *linter) checkSchemas() {
+	components := l.doc.Components
+	if components == nil {
+		return
+	}
+	for _, name := range sortedKeys(components.Schemas) {
+		l.walkSchema("/components/schemas/"+escape(name), components.Schemas[name], false)
+	}
+	for _, name := range sortedKeys(components.Parameters) {
+		if param := components.Parameters[name]; param.Ref == "" && param.Value != nil && param.Value.Schema != nil {
+			l.walkSchema("/components/parameters/"+escape(name)+"/schema", param.Value.Schema, true)
+		}
+	}
+	for _, name := range sortedKeys(components.RequestBodies) {
+		if body := components.RequestBodies[name]; body.Ref == "" && body.Value != nil {
+			l.walkContent("/components/requestBodies/"+escape(name)+"/content", body.Value.Content)
+		}
+	}
+	for _, name := range sortedKeys(components.Responses) {
+		if response := components.Responses[name]; response.Ref == "" && response.Value != nil {
+			l.walkContent("/components/responses/"+escape(name)+"/content", response.Value.Content)
+		}
+	}
+}
+
+// walkSchema checks a schema and the schemas nested in it. References are checked where they are declared.
+func (l *linter) walkSchema(pointer string, ref *openapi3.SchemaRef, nested bool) {
+	if ref == nil || ref.Ref != "" || ref.Value == nil {
+		return
+	}
+	schema := ref.Value
+
+	if schema.Type.Is("object") {
+		if nested {
+			l.report(RuleInlineObject, pointer, "inline object schema is generated as Dict[str, Any], move it to components.schemas")
+		}
+		l.checkOrder(pointer, schema)
+
+		names := make(map[string]string)
+		for _, name := range sortedKeys(schema.Properties) {
+			varName := python.ToPythonVarName(name)
+			if existing, ok := names[varName]; ok {
+				l.report(RuleNameCollision, pointer, "properties %s and %s are both generated as %s", existing, name, varName)
+			}
+			names[varName] = name
+		}
+	}
+
+	if len(schema.Enum) > 0 {
+		enumNames, ok := schema.Extensions["x-coze-enum-names"].([]any)
+		if !ok {
+			l.report(RuleMissingEnumNames, pointer, "enum has no x-coze-enum-names")
+		} else if len(enumNames) != len(schema.Enum) {
+			l.report(RuleMissingEnumNames, pointer, "x-coze-enum-names has %d names for %d values", len(enumNames), len(schema.Enum))
+		}
+	}
+
+	l.checkDescription(pointer, schema.Title, schema.Description)
+
+	for _, name := range sortedKeys(schema.Properties) {
+		l.walkSchema(pointer+"/properties/"+escape(name), schema.Properties[name], true)
+	}
+	l.walkSchema(pointer+"/items", schema.Items, true)
+	l.walkSchema(pointer+"/additionalProperties", schema.AdditionalProperties.Schema, true)
+	for i, s := range schema.AllOf {
+		l.walkSchema(fmt.Sprintf("%s/allOf/%d", pointer, i), s, true)
+	}
+	for i, s := range schema.OneOf {
+		l.walkSchema(fmt.Sprintf("%s/oneOf/%d", pointer, i), s, true)
+	}
+	for i, s := range schema.AnyOf {
+		l.walkSchema(fmt.Sprintf("%s/anyOf/%d", pointer, i), s, true)
+	}
+}
+
+// checkOrder checks that x-coze-order exists and lists every property, as the parser drops the others
+func (l *linter) checkOrder(pointer string, schema *openapi3.Schema) {
+	if len(schema.Properties) == 0 {
+		return
+	}
+	order, ok := schema.Extensions["x-coze-order"].([]any)
+	if !ok {
+		l.report(RuleMissingOrder, pointer, "object schema has no x-coze-order")
+		return
+	}
+	ordered := make(map[string]bool)
+	for _, name := range order {
+		if name, ok := name.(string); ok {
+			ordered[name] = true
+		}
+	}
+	for _, name := range sortedKeys(schema.Properties) {
+		if !ordered[name] {
+			l.report(RuleMissingOrder, pointer, "x-coze-order does not list property %s, it is dropped", name)
+		}
+	}
+}
+
+// checkDescription reports Chinese only descriptions next to English titles
+func (l *linter) checkDescription(pointer, title, description string) {
+	if title == "" || description == "" {
+		return
+	}
+	if !hasHan(title) && hasLatin(title) && hasHan(description) && !hasLatin(description) {
+		l.report(RuleChineseDescription, pointer, "description is only in Chinese while the title %q is in English", title)
+	}
+}
+
+// checkUnusedSchemas reports component schemas that are not reachable from any operation
+func (l *linter) checkUnusedSchemas() {
+	if l.root == nil || l.doc.Components == nil || len(l.doc.Components.Schemas) == 0 {
+		return
+	}
+	schemas := mappingValue(mappingValue(l.root, "components"), "schemas")
+
+	// Start from every reference outside of components.schemas
+	var pending []string
+	for i := 0; i+1 < len(l.root.Content); i += 2 {
+		if l.root.Content[i].Value != "components" {
+			pending = append(pending, collectRefs(l.root.Content[i+1])...)
+			continue
+		}
+		components := l.root.Content[i+1]
+		for j := 0; j+1 < len(components.Content); j += 2 {
+			if components.Content[j].Value != "schemas" {
+				pending = append(pending, collectRefs(components.Content[j+1])...)
+			}
+		}
+	}
+
+	used := make(map[string]bool)
+	for len(pending) > 0 {
+		ref := pending[0]
+		pending = pending[1:]
+		name, ok := localSchemaName(ref)
+		if !ok || used[name] {
+			continue
+		}
+		used[name] = true
+		if schema := mappingValue(schemas, name); schema != nil {
+			pending = append(pending, collectRefs(schema)...)
+		}
+	}
+
+	for _, name := range sortedKeys(l.doc.Components.Schemas) {
+		if !used[name] {
+			l.report(RuleUnusedSchema, "/components/schemas/"+escape(name), "schema %s is not used by any operation", name)
+		}
+	}
+}
+
+// localSchemaName returns the schema a local reference points into,
+// e.g. #/components/schemas/Bot/properties/name -> Bot
+func localSchemaName(ref string) (string, bool) {
+	name, ok := strings.CutPrefix(ref, "#/components/schemas/")
+	if !ok {
+		return "", false
+	}
+	name, _, _ = strings.Cut(name, "/")
+	return unescape(name), name != ""
+}
+
+// collectRefs returns all $ref values below a node
+func collectRefs(node *yaml.Node) []string {
+	if node == nil {
+		return nil
+	}
+	var refs []string
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == "$ref" && node.Content[i+1].Kind == yaml.ScalarNode {
+				refs = append(refs, node.Content[i+1].Value)
+			}
+		}
+	}
+	for _, child := range node.Content {
+		refs = append(refs, collectRefs(child)...)
+	}
+	return refs
+}
+
+// lineOf returns the line of the node a JSON pointer points to, or of its closest existing parent
+func lineOf(root *yaml.Node, pointer string) int {
+	if root == nil {
+		return 0
+	}
+	node := root
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		if token == "" {
+			continue
+		}
+		var next *yaml.Node
+		switch node.Kind {
+		case yaml.MappingNode:
+			token = unescape(token)
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == token {
+					next = node.Content[i+1]
+					break
+				}
+			}
+		case yaml.SequenceNode:
+			var index int
+			if _, err := fmt.Sscanf(token, "%d", &index); err == nil && index >= 0 && index < len(node.Content) {
+				next = node.Content[index]
+			}
+		}
+		if next == nil {
+			break
+		}
+		node = next
+	}
+	return node.Line
+}
+
+// mappingValue returns the value of a key in a mapping node
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// escape escapes a JSON pointer token
+func escape(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+// unescape unescapes a JSON pointer token
+func unescape(token string) string {
+	return strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+}
+
+func hasHan(s string) bool {
+	for _, r := range s {
+		if unicode.Is(unicode.Han, r) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasLatin(s string) bool {
+	for _, r := range s {
+		if r < unicode.MaxASCII && unicode.IsLetter(r) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
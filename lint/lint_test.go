@@ -0,0 +1,141 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/coze-dev/coze-sdk-gen/parser"
+	"github.com/stretchr/testify/require"
+)
+
+const spec = `openapi: 3.0.3
+info:
+  title: test
+  version: "1"
+paths:
+  /v1/bots:
+    get:
+      operationId: ListBots
+      parameters:
+        - name: pageSize
+          in: query
+          schema:
+            type: integer
+        - name: page_size
+          in: query
+          schema:
+            type: integer
+      responses:
+        "200":
+          description: ""
+          content:
+            application/json:
+              schema:
+                type: object
+                x-coze-order: [data]
+                properties:
+                  data:
+                    $ref: "#/components/schemas/Bot"
+  /v2/bots:
+    get:
+      operationId: ListBots
+      tags:
+        - bots
+components:
+  schemas:
+    Bot:
+      type: object
+      x-coze-order: [bot_id, status]
+      properties:
+        bot_id:
+          type: string
+        status:
+          $ref: "#/components/schemas/BotStatus"
+        icon:
+          title: Icon
+          description: 图标
+          type: object
+          properties:
+            url:
+              type: string
+    BotStatus:
+      type: integer
+      enum: [0, 1]
+    Unused:
+      type: string
+`
+
+func TestLint(t *testing.T) {
+	findings, err := Lint(parser.Spec{Path: "openapi.yaml", Content: []byte(spec)})
+	require.NoError(t, err)
+
+	rules := make(map[string][]string)
+	for _, finding := range findings {
+		require.NotZero(t, finding.Line, finding.Pointer)
+		rules[finding.Rule] = append(rules[finding.Rule], finding.Pointer)
+	}
+	require.Equal(t, []string{"/paths/~1v2~1bots/get"}, rules[RuleDuplicateOperationID.ID])
+	require.Equal(t, []string{"/paths/~1v1~1bots/get"}, rules[RuleMissingTags.ID])
+	require.Equal(t, []string{"/paths/~1v1~1bots/get"}, rules[RuleNameCollision.ID])
+	require.Equal(t, []string{"/components/schemas/Bot", "/components/schemas/Bot/properties/icon"}, rules[RuleMissingOrder.ID])
+	require.Equal(t, []string{"/components/schemas/BotStatus"}, rules[RuleMissingEnumNames.ID])
+	require.Equal(t, []string{"/components/schemas/Bot/properties/icon"}, rules[RuleInlineObject.ID])
+	require.Equal(t, []string{"/components/schemas/Bot/properties/icon"}, rules[RuleChineseDescription.ID])
+	require.Equal(t, []string{"/components/schemas/Unused"}, rules[RuleUnusedSchema.ID])
+	require.True(t, HasErrors(findings))
+}
+
+func TestLint_MultipleSpecs(t *testing.T) {
+	spec := func(path string) string {
+		return `
+openapi: 3.0.3
+info:
+  title: test
+  version: "1"
+paths:
+  ` + path + `:
+    get:
+      operationId: ListBots
+      tags:
+        - bots
+      responses:
+        "200":
+          description: ok
+`
+	}
+	findings, err := Lint(
+		parser.Spec{Path: "bots.yaml", Content: []byte(spec("/v1/bots"))},
+		parser.Spec{Path: "bots_v2.yaml", Content: []byte(spec("/v2/bots"))},
+	)
+	require.NoError(t, err)
+	require.Len(t, findings, 1)
+	require.Equal(t, RuleDuplicateOperationID.ID, findings[0].Rule)
+	require.Equal(t, "bots_v2.yaml", findings[0].File)
+	require.Equal(t, "operationId ListBots is also used by GET /v1/bots in bots.yaml", findings[0].Message)
+}
+
+func TestWrite(t *testing.T) {
+	findings := []Finding{{
+		Rule:     RuleMissingTags.ID,
+		Severity: SeverityWarning,
+		Message:  "GET /v1/bots has no tags",
+		File:     "openapi.yaml",
+		Pointer:  "/paths/~1v1~1bots/get",
+		Line:     7,
+	}}
+
+	var text bytes.Buffer
+	require.NoError(t, Write(&text, FormatText, findings))
+	require.Equal(t, "openapi.yaml:7: warning: GET /v1/bots has no tags (/paths/~1v1~1bots/get) [missing-tags]\n1 problem(s) found\n", text.String())
+
+	var sarif bytes.Buffer
+	require.NoError(t, Write(&sarif, FormatSARIF, findings))
+	var log sarifLog
+	require.NoError(t, json.Unmarshal(sarif.Bytes(), &log))
+	require.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs[0].Results, 1)
+	require.Equal(t, 7, log.Runs[0].Results[0].Locations[0].PhysicalLocation.Region.StartLine)
+
+	require.Error(t, Write(&text, Format("xml"), findings))
+}
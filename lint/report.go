@@ -0,0 +1,153 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Format represents the output format of a report
+type Format string
+
+const (
+	FormatText  Format = "text"
+	FormatJSON  Format = "json"
+	FormatSARIF Format = "sarif"
+)
+
+// Write writes findings in the given format
+func Write(w io.Writer, format Format, findings []Finding) error {
+	switch format {
+	case FormatText:
+		return writeText(w, findings)
+	case FormatJSON:
+		return writeJSON(w, findings)
+	case FormatSARIF:
+		return writeSARIF(w, findings)
+	default:
+		return fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+// HasErrors checks if any finding has the error severity
+func HasErrors(findings []Finding) bool {
+	for _, finding := range findings {
+		if finding.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// writeText writes one finding per line, e.g. openapi.yaml:12: warning: ... [missing-tags]
+func writeText(w io.Writer, findings []Finding) error {
+	for _, finding := range findings {
+		if _, err := fmt.Fprintf(w, "%s:%d: %s: %s (%s) [%s]\n", finding.File, finding.Line, finding.Severity, finding.Message, finding.Pointer, finding.Rule); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "%d problem(s) found\n", len(findings))
+	return err
+}
+
+func writeJSON(w io.Writer, findings []Finding) error {
+	if findings == nil {
+		findings = []Finding{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(findings)
+}
+
+// SARIF 2.1.0 log, only the parts needed to annotate findings
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+	DefaultConfig    sarifConfig  `json:"defaultConfiguration"`
+}
+
+type sarifConfig struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+func writeSARIF(w io.Writer, findings []Finding) error {
+	run := sarifRun{
+		Tool:    sarifTool{Driver: sarifDriver{Name: "coze-sdk-gen"}},
+		Results: []sarifResult{},
+	}
+	for _, rule := range Rules() {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+			ID:               rule.ID,
+			ShortDescription: sarifMessage{Text: rule.Description},
+			DefaultConfig:    sarifConfig{Level: string(rule.Severity)},
+		})
+	}
+	for _, finding := range findings {
+		location := sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: finding.File}}
+		if finding.Line > 0 {
+			location.Region = &sarifRegion{StartLine: finding.Line}
+		}
+		run.Results = append(run.Results, sarifResult{
+			RuleID:    finding.Rule,
+			Level:     string(finding.Severity),
+			Message:   sarifMessage{Text: finding.Message},
+			Locations: []sarifLocation{{PhysicalLocation: location}},
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	})
+}
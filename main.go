@@ -3,24 +3,38 @@ package main
 import (
 	"context"
 	"fmt"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/coze-dev/coze-sdk-gen/formater"
 	"github.com/coze-dev/coze-sdk-gen/generator"
+	"github.com/coze-dev/coze-sdk-gen/overlay"
+	"github.com/coze-dev/coze-sdk-gen/parser"
 	"github.com/coze-dev/coze-sdk-gen/writer"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
 var (
 	lang       string
 	outputPath string
 	module     string
+	overlays   []string
+	docLang    string
+	pkgName    string
+	pkgVersion string
 )
 
 func init() {
 	rootCmd.Flags().StringVarP(&lang, "lang", "l", "", "SDK language to generate")
 	rootCmd.Flags().StringVarP(&outputPath, "output", "o", "", "Output directory path for the generated SDK")
 	rootCmd.Flags().StringVarP(&module, "module", "m", "", "Specific module to generate")
+	rootCmd.Flags().StringArrayVar(&overlays, "overlay", nil, "OpenAPI Overlay applied to the specs before parsing, can be repeated")
+	rootCmd.Flags().StringVar(&docLang, "doc-lang", "", "Language of the generated documentation: en, zh or both (default: as in the specs)")
+	rootCmd.Flags().StringVar(&pkgName, "package-name", "", "Generate a pyproject.toml for a standalone package with this name")
+	rootCmd.Flags().StringVar(&pkgVersion, "package-version", "0.1.0", "Version of the standalone package")
 
 	// Mark flags as required
 	rootCmd.MarkFlagRequired("lang")
@@ -33,26 +47,41 @@ func init() {
 		if !supportedLangs[lang] {
 			return fmt.Errorf("unsupported language %q (currently only supports 'python')", lang)
 		}
+		switch docLang {
+		case "", "en", "zh", "both":
+		default:
+			return fmt.Errorf("unsupported documentation language %q (supports 'en', 'zh' and 'both')", docLang)
+		}
 		return nil
 	}
 }
 
 var rootCmd = &cobra.Command{
-	Use:   "coze-sdk-gen <openapi.yaml>",
+	Use:   "coze-sdk-gen <openapi.yaml|dir>...",
 	Short: "Generate SDK from OpenAPI specification",
 	Long: `A generator tool that creates SDK from OpenAPI specification.
+Several specs, or directories of specs, are merged into a single SDK.
 Currently supports generating Python SDK.`,
-	Args: cobra.ExactArgs(1),
+	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		// Read the YAML file
-		yamlPath := args[0]
-		yamlContent, err := os.ReadFile(yamlPath)
+		// Read the YAML files
+		specs, err := readSpecs(args)
 		if err != nil {
-			return fmt.Errorf("failed to read YAML file: %v", err)
+			return err
+		}
+
+		// Patch the specs with the overlays
+		if specs, err = applyOverlays(specs, overlays); err != nil {
+			return err
 		}
 
 		// Generate SDK code based on language
-		files, err := generator.Generate(context.Background(), lang, yamlContent, module)
+		files, err := generator.Generate(context.Background(), lang, specs, generator.Options{
+			Module:         module,
+			DocLang:        docLang,
+			PackageName:    pkgName,
+			PackageVersion: pkgVersion,
+		})
 		if err != nil {
 			return err
 		}
@@ -71,6 +100,90 @@ Currently supports generating Python SDK.`,
 	},
 }
 
+// readSpecs reads the specs given as arguments. Directories are expanded to the OpenAPI documents
+// they contain, other YAML or JSON files there (e.g. referenced by the documents) are skipped.
+func readSpecs(paths []string) ([]parser.Spec, error) {
+	var specs []parser.Spec
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read YAML file: %v", err)
+		}
+		if !info.IsDir() {
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read YAML file: %v", err)
+			}
+			specs = append(specs, parser.Spec{Path: path, Content: content})
+			continue
+		}
+
+		err = filepath.WalkDir(path, func(file string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			switch filepath.Ext(file) {
+			case ".yaml", ".yml", ".json":
+			default:
+				return nil
+			}
+			content, err := os.ReadFile(file)
+			if err != nil {
+				return fmt.Errorf("failed to read YAML file: %v", err)
+			}
+			var header struct {
+				OpenAPI string `yaml:"openapi"`
+			}
+			if err := yaml.Unmarshal(content, &header); err != nil || header.OpenAPI == "" {
+				return nil
+			}
+			specs = append(specs, parser.Spec{Path: file, Content: content})
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("no OpenAPI document found in %s", strings.Join(paths, ", "))
+	}
+	return specs, nil
+}
+
+// applyOverlays applies the overlays in order to every spec, and reports the actions
+// that did not match anything in any of the specs
+func applyOverlays(specs []parser.Spec, paths []string) ([]parser.Spec, error) {
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read overlay file: %v", err)
+		}
+		o, err := overlay.Parse(content)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		matches := make([]int, len(o.Actions))
+		for i, spec := range specs {
+			patched, specMatches, err := o.Apply(spec.Content)
+			if err != nil {
+				return nil, fmt.Errorf("failed to apply overlay %s to %s: %w", path, spec.Path, err)
+			}
+			specs[i].Content = patched
+			for j, count := range specMatches {
+				matches[j] += count
+			}
+		}
+
+		for i, action := range o.Actions {
+			if matches[i] == 0 {
+				fmt.Fprintf(os.Stderr, "Warning: overlay %s action %d matched nothing: %s\n", path, i, action.Target)
+			}
+		}
+	}
+	return specs, nil
+}
+
 func main() {
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
@@ -0,0 +1,266 @@
+// Package markdown renders the Markdown of spec descriptions as reStructuredText, the markup of
+// the docstrings the API reference is built from.
+package markdown
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	fencePattern     = regexp.MustCompile("^\\s*(```|~~~)")
+	headingPattern   = regexp.MustCompile(`^\s{0,3}#{1,6}\s+(.*?)\s*#*\s*$`)
+	listItemPattern  = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+	tableRowPattern  = regexp.MustCompile(`^\s*\|`)
+	tableSepPattern  = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?\s*$`)
+	codeSpanPattern  = regexp.MustCompile("(`+)(.+?)(`+)")
+	imagePattern     = regexp.MustCompile(`!\[([^\]]*)\]\(\s*([^)\s]+)[^)]*\)`)
+	linkPattern      = regexp.MustCompile(`\[([^\]]+)\]\(\s*([^)\s]+)[^)]*\)`)
+	autolinkPattern  = regexp.MustCompile(`<((?:https?|mailto):[^>\s]+)>`)
+	anchorPattern    = regexp.MustCompile(`(?is)<a\s[^>]*href\s*=\s*["']([^"']+)["'][^>]*>(.*?)</a>`)
+	lineBreakPattern = regexp.MustCompile(`(?i)<br\s*/?>`)
+	tagPattern       = regexp.MustCompile(`</?[a-zA-Z][^>]*>`)
+	escapePattern    = regexp.MustCompile("\\\\([!-/:-@\\[-`{-~])")
+	spacePattern     = regexp.MustCompile(`\s+`)
+)
+
+// ToRST renders Markdown as reStructuredText, reflowing paragraphs and list items to width.
+// Links are kept, images become links or are dropped without alt text, tables become lists
+// and HTML is reduced to its text.
+func ToRST(text string, width int) string {
+	var blocks []string
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		switch {
+		case strings.TrimSpace(line) == "":
+			i++
+
+		case fencePattern.MatchString(line):
+			fence := fencePattern.FindStringSubmatch(line)[1]
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fence); i++ {
+				code = append(code, "    "+lines[i])
+			}
+			i++
+			blocks = append(blocks, "::\n\n"+strings.Join(code, "\n"))
+
+		case headingPattern.MatchString(line):
+			blocks = append(blocks, wrap(inline(headingPattern.FindStringSubmatch(line)[1]), width, "", ""))
+			i++
+
+		case tableRowPattern.MatchString(line) && i+1 < len(lines) && tableSepPattern.MatchString(lines[i+1]):
+			header := tableCells(line)
+			var items []string
+			for i += 2; i < len(lines) && tableRowPattern.MatchString(lines[i]); i++ {
+				items = append(items, wrap(tableRow(header, tableCells(lines[i])), width, "- ", "  "))
+			}
+			if len(items) > 0 {
+				blocks = append(blocks, strings.Join(items, "\n"))
+			}
+
+		case listItemPattern.MatchString(line):
+			var block string
+			block, i = list(lines, i, width)
+			blocks = append(blocks, block)
+
+		default:
+			var paragraph []string
+			for ; i < len(lines) && strings.TrimSpace(lines[i]) != "" && (len(paragraph) == 0 || !startsBlock(lines, i)); i++ {
+				paragraph = append(paragraph, lines[i])
+			}
+			blocks = append(blocks, wrap(inline(strings.Join(paragraph, " ")), width, "", ""))
+		}
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+// startsBlock checks if a line interrupts a paragraph
+func startsBlock(lines []string, i int) bool {
+	line := lines[i]
+	return fencePattern.MatchString(line) || headingPattern.MatchString(line) || listItemPattern.MatchString(line) ||
+		(tableRowPattern.MatchString(line) && i+1 < len(lines) && tableSepPattern.MatchString(lines[i+1]))
+}
+
+// list renders the list starting at line i, nested lists are indented below their parent item
+// and separated by blank lines as reStructuredText requires. It returns the index of the line
+// following the list.
+func list(lines []string, i, width int) (string, int) {
+	type item struct {
+		level  int
+		marker string
+		text   string
+	}
+
+	var items []item
+	var indents []int
+	for i < len(lines) {
+		match := listItemPattern.FindStringSubmatch(lines[i])
+		if match == nil {
+			break
+		}
+		indent := len(strings.ReplaceAll(match[1], "\t", "    "))
+		for len(indents) > 0 && indent < indents[len(indents)-1] {
+			indents = indents[:len(indents)-1]
+		}
+		if len(indents) == 0 || indent > indents[len(indents)-1] {
+			indents = append(indents, indent)
+		}
+		marker := match[2]
+		if marker == "*" || marker == "+" {
+			marker = "-"
+		}
+		text := []string{match[3]}
+
+		// Continuation lines belong to the item until a blank line or another block
+		for i++; i < len(lines) && strings.TrimSpace(lines[i]) != "" && !startsBlock(lines, i); i++ {
+			text = append(text, strings.TrimSpace(lines[i]))
+		}
+		items = append(items, item{level: len(indents) - 1, marker: strings.Replace(marker, ")", ".", 1), text: strings.Join(text, " ")})
+
+		// A blank line inside a list continues it if the next item follows
+		if i+1 < len(lines) && strings.TrimSpace(lines[i]) == "" && listItemPattern.MatchString(lines[i+1]) {
+			i++
+		}
+	}
+
+	var out strings.Builder
+	offsets := []int{0}
+	for n, it := range items {
+		for len(offsets) > it.level+1 {
+			offsets = offsets[:len(offsets)-1]
+		}
+		if n > 0 {
+			out.WriteString("\n")
+			if it.level != items[n-1].level {
+				out.WriteString("\n")
+			}
+		}
+		if it.level >= len(offsets) {
+			prev := items[n-1]
+			offsets = append(offsets, offsets[len(offsets)-1]+len(prev.marker)+1)
+		}
+		indent := strings.Repeat(" ", offsets[it.level])
+		out.WriteString(wrap(inline(it.text), width, indent+it.marker+" ", indent+strings.Repeat(" ", len(it.marker)+1)))
+	}
+	return out.String(), i
+}
+
+// tableCells splits a table row into its trimmed cells
+func tableCells(row string) []string {
+	row = strings.TrimSpace(row)
+	row = strings.TrimSuffix(strings.TrimPrefix(row, "|"), "|")
+	cells := strings.Split(row, "|")
+	for i := range cells {
+		cells[i] = strings.TrimSpace(cells[i])
+	}
+	return cells
+}
+
+// tableRow renders a table row as a list item naming each cell after its column
+func tableRow(header, cells []string) string {
+	parts := make([]string, 0, len(cells))
+	for i, cell := range cells {
+		if cell == "" {
+			continue
+		}
+		if i < len(header) && header[i] != "" {
+			parts = append(parts, fmt.Sprintf("%s: %s", inline(header[i]), inline(cell)))
+		} else {
+			parts = append(parts, inline(cell))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// inline renders inline Markdown: code spans, links, images, escapes and HTML
+func inline(text string) string {
+	// Code spans and links are replaced by placeholders so that their content is kept as is
+	var spans []string
+	placeholder := func(span string) string {
+		spans = append(spans, span)
+		return fmt.Sprintf("\x00%d\x00", len(spans)-1)
+	}
+
+	text = codeSpanPattern.ReplaceAllStringFunc(text, func(match string) string {
+		groups := codeSpanPattern.FindStringSubmatch(match)
+		if groups[1] != groups[3] {
+			return match
+		}
+		return placeholder("``" + strings.TrimSpace(groups[2]) + "``")
+	})
+	text = imagePattern.ReplaceAllStringFunc(text, func(match string) string {
+		groups := imagePattern.FindStringSubmatch(match)
+		if strings.TrimSpace(groups[1]) == "" {
+			return ""
+		}
+		return placeholder(link(groups[1], groups[2]))
+	})
+	text = linkPattern.ReplaceAllStringFunc(text, func(match string) string {
+		groups := linkPattern.FindStringSubmatch(match)
+		return placeholder(link(groups[1], groups[2]))
+	})
+	text = anchorPattern.ReplaceAllStringFunc(text, func(match string) string {
+		groups := anchorPattern.FindStringSubmatch(match)
+		return placeholder(link(tagPattern.ReplaceAllString(groups[2], ""), groups[1]))
+	})
+	text = autolinkPattern.ReplaceAllString(text, "$1")
+	text = lineBreakPattern.ReplaceAllString(text, " ")
+	text = tagPattern.ReplaceAllString(text, "")
+	text = unescape(text)
+	text = html.UnescapeString(text)
+	text = strings.TrimSpace(spacePattern.ReplaceAllString(text, " "))
+
+	for i, span := range spans {
+		text = strings.Replace(text, fmt.Sprintf("\x00%d\x00", i), span, 1)
+	}
+	return text
+}
+
+// unescape removes the Markdown backslash escapes, characters that are inline markup in
+// reStructuredText as well stay escaped
+func unescape(text string) string {
+	return escapePattern.ReplaceAllStringFunc(text, func(match string) string {
+		if strings.ContainsAny(match[1:], "*`_|") {
+			return match
+		}
+		return match[1:]
+	})
+}
+
+// link renders an anonymous reStructuredText hyperlink, which may repeat its text
+func link(text, url string) string {
+	text = strings.TrimSpace(unescape(html.UnescapeString(text)))
+	if text == "" || text == url {
+		return url
+	}
+	return fmt.Sprintf("`%s <%s>`__", text, url)
+}
+
+// wrap reflows text to width, the first line starts with prefix and the others with indent.
+// Words longer than the width, such as URLs, are not broken.
+func wrap(text string, width int, prefix, indent string) string {
+	var out strings.Builder
+	out.WriteString(prefix)
+	lineLength := utf8.RuneCountInString(prefix)
+	atLineStart := true
+	for _, word := range strings.Fields(text) {
+		wordLength := utf8.RuneCountInString(word)
+		if !atLineStart && lineLength+1+wordLength > width {
+			out.WriteString("\n" + indent)
+			lineLength = utf8.RuneCountInString(indent)
+			atLineStart = true
+		}
+		if !atLineStart {
+			out.WriteString(" ")
+			lineLength++
+		}
+		out.WriteString(word)
+		lineLength += wordLength
+		atLineStart = false
+	}
+	return strings.TrimRight(out.String(), " ")
+}
@@ -0,0 +1,61 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestToRST(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     string
+	}{
+		{
+			name:     "paragraphs are reflowed",
+			markdown: "The ID of the\nbot.\n\n\nUse `bot_id` from\nthe URL.",
+			want:     "The ID of the bot.\n\nUse ``bot_id`` from the URL.",
+		},
+		{
+			name:     "escapes and entities",
+			markdown: `Mandatory when chunk\_type=1, e.g. 341\*\*\*\* &lt;id&gt; \#1`,
+			want:     `Mandatory when chunk\_type=1, e.g. 341\*\*\*\* <id> #1`,
+		},
+		{
+			name:     "links and images",
+			markdown: "![](https://p9.byteimg.com/a.png =168x119)\nSee [retrieve chat](https://www.coze.cn/docs/retrieve_chat) or ![diagram](https://a.com/d.png) <https://coze.com>",
+			want:     "See `retrieve chat <https://www.coze.cn/docs/retrieve_chat>`__ or\n`diagram <https://a.com/d.png>`__ https://coze.com",
+		},
+		{
+			name:     "html",
+			markdown: `Line<br/>break with <a href="https://coze.com">a <b>link</b></a> and <span>text</span>`,
+			want:     "Line break with `a link <https://coze.com>`__ and text",
+		},
+		{
+			name:     "lists",
+			markdown: "Values:\n- **true**: use the streaming response, which sends events as they are\n  generated.\n- **false**: wait for the response\n  1. first\n  2. second\n* last",
+			want:     "Values:\n\n- **true**: use the streaming response, which sends events as they are\n  generated.\n- **false**: wait for the response\n\n  1. first\n  2. second\n\n- last",
+		},
+		{
+			name:     "tables",
+			markdown: "| Name | Description |\n| --- | :---: |\n| `id` | The ID |\n| name | |",
+			want:     "- Name: ``id``, Description: The ID\n- Name: name",
+		},
+		{
+			name:     "code blocks",
+			markdown: "Request:\n```json\n{\"a\": 1}\n```",
+			want:     "Request:\n\n::\n\n    {\"a\": 1}",
+		},
+		{
+			name:     "headings",
+			markdown: "## Notes ##\nText",
+			want:     "Notes\n\nText",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, ToRST(tt.markdown, 72))
+		})
+	}
+}
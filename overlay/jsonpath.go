@@ -0,0 +1,284 @@
+package overlay
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// selectorKind represents the kind of a JSONPath segment
+type selectorKind int
+
+const (
+	selectorName selectorKind = iota
+	selectorWildcard
+	selectorIndex
+	selectorFilter
+)
+
+// selector is a single segment of a JSONPath, e.g. .paths, [*], [0] or [?(@.name == 'id')]
+type selector struct {
+	kind      selectorKind
+	name      string
+	index     int
+	filter    *filter
+	recursive bool // preceded by .., applies to all descendants
+}
+
+// filter is a [?(...)] predicate comparing a relative path to a literal, or checking it exists
+type filter struct {
+	path     *path
+	operator string // ==, != or empty for an existence check
+	value    string
+}
+
+// path is a parsed JSONPath, supporting the subset used by overlays:
+// $, .name, ['name'], .*, [*], [n], ..name and [?(@.path == 'value')]
+type path struct {
+	selectors []selector
+}
+
+// match is a node selected by a path together with its parent
+type match struct {
+	node   *yaml.Node
+	parent *yaml.Node
+}
+
+// parsePath parses a JSONPath expression starting with $
+func parsePath(expr string) (*path, error) {
+	expr = strings.TrimSpace(expr)
+	if !strings.HasPrefix(expr, "$") {
+		return nil, fmt.Errorf("JSONPath %q must start with $", expr)
+	}
+	return parseSelectors(expr, expr[1:])
+}
+
+// parseSelectors parses the segments following the root of a JSONPath
+func parseSelectors(expr, rest string) (*path, error) {
+	p := &path{}
+	for rest != "" {
+		recursive := false
+		switch {
+		case strings.HasPrefix(rest, ".."):
+			recursive = true
+			rest = rest[2:]
+			if strings.HasPrefix(rest, "[") {
+				break
+			}
+			fallthrough
+		case strings.HasPrefix(rest, "."):
+			rest = strings.TrimPrefix(rest, ".")
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			name := rest[:end]
+			rest = rest[end:]
+			if name == "" {
+				return nil, fmt.Errorf("JSONPath %q has an empty name", expr)
+			}
+			if name == "*" {
+				p.selectors = append(p.selectors, selector{kind: selectorWildcard, recursive: recursive})
+			} else {
+				p.selectors = append(p.selectors, selector{kind: selectorName, name: name, recursive: recursive})
+			}
+			continue
+		case strings.HasPrefix(rest, "["):
+		default:
+			return nil, fmt.Errorf("JSONPath %q has an unexpected %q", expr, rest)
+		}
+
+		end := closingBracket(rest)
+		if end < 0 {
+			return nil, fmt.Errorf("JSONPath %q has an unclosed [", expr)
+		}
+		sel, err := parseBracket(expr, strings.TrimSpace(rest[1:end]))
+		if err != nil {
+			return nil, err
+		}
+		sel.recursive = recursive
+		p.selectors = append(p.selectors, sel)
+		rest = rest[end+1:]
+	}
+	return p, nil
+}
+
+// closingBracket returns the index of the ] closing the [ at the start of s, skipping quoted strings
+func closingBracket(s string) int {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// parseBracket parses the content of a [...] segment
+func parseBracket(expr, content string) (selector, error) {
+	switch {
+	case content == "*":
+		return selector{kind: selectorWildcard}, nil
+	case isQuoted(content):
+		return selector{kind: selectorName, name: content[1 : len(content)-1]}, nil
+	case strings.HasPrefix(content, "?"):
+		f, err := parseFilter(expr, strings.TrimSpace(content[1:]))
+		if err != nil {
+			return selector{}, err
+		}
+		return selector{kind: selectorFilter, filter: f}, nil
+	default:
+		index, err := strconv.Atoi(content)
+		if err != nil {
+			return selector{}, fmt.Errorf("JSONPath %q has an unsupported selector [%s]", expr, content)
+		}
+		return selector{kind: selectorIndex, index: index}, nil
+	}
+}
+
+// parseFilter parses a filter expression such as (@.in == 'query')
+func parseFilter(expr, content string) (*filter, error) {
+	if strings.HasPrefix(content, "(") && strings.HasSuffix(content, ")") {
+		content = strings.TrimSpace(content[1 : len(content)-1])
+	}
+	if !strings.HasPrefix(content, "@") {
+		return nil, fmt.Errorf("JSONPath %q has a filter not starting with @", expr)
+	}
+
+	f := &filter{}
+	relative := content
+	for _, operator := range []string{"==", "!="} {
+		if left, right, ok := strings.Cut(content, operator); ok {
+			relative = strings.TrimSpace(left)
+			f.operator = operator
+			f.value = strings.TrimSpace(right)
+			if isQuoted(f.value) {
+				f.value = f.value[1 : len(f.value)-1]
+			}
+			break
+		}
+	}
+
+	p, err := parseSelectors(expr, relative[1:])
+	if err != nil {
+		return nil, err
+	}
+	f.path = p
+	return f, nil
+}
+
+func isQuoted(s string) bool {
+	return len(s) >= 2 && (s[0] == '\'' || s[0] == '"') && s[len(s)-1] == s[0]
+}
+
+// selectFrom returns the nodes selected by the path from root
+func (p *path) selectFrom(root *yaml.Node) []match {
+	matches := []match{{node: root}}
+	for _, sel := range p.selectors {
+		var next []match
+		for _, m := range matches {
+			if sel.recursive {
+				for _, descendant := range descendants(m) {
+					next = append(next, sel.apply(descendant.node)...)
+				}
+				continue
+			}
+			next = append(next, sel.apply(m.node)...)
+		}
+		matches = next
+	}
+	return matches
+}
+
+// apply returns the children of a node selected by the selector
+func (s selector) apply(node *yaml.Node) []match {
+	var result []match
+	switch s.kind {
+	case selectorName:
+		if value := mappingValue(node, s.name); value != nil {
+			result = append(result, match{node: value, parent: node})
+		}
+	case selectorWildcard, selectorFilter:
+		for _, child := range children(node) {
+			if s.kind == selectorFilter && !s.filter.test(child) {
+				continue
+			}
+			result = append(result, match{node: child, parent: node})
+		}
+	case selectorIndex:
+		if node.Kind == yaml.SequenceNode {
+			index := s.index
+			if index < 0 {
+				index += len(node.Content)
+			}
+			if index >= 0 && index < len(node.Content) {
+				result = append(result, match{node: node.Content[index], parent: node})
+			}
+		}
+	}
+	return result
+}
+
+// test checks if a node satisfies the filter
+func (f *filter) test(node *yaml.Node) bool {
+	values := f.path.selectFrom(node)
+	switch f.operator {
+	case "==":
+		for _, value := range values {
+			if value.node.Kind == yaml.ScalarNode && value.node.Value == f.value {
+				return true
+			}
+		}
+		return false
+	case "!=":
+		for _, value := range values {
+			if value.node.Kind == yaml.ScalarNode && value.node.Value == f.value {
+				return false
+			}
+		}
+		return true
+	default:
+		return len(values) > 0
+	}
+}
+
+// children returns the values of a mapping or the items of a sequence
+func children(node *yaml.Node) []*yaml.Node {
+	switch node.Kind {
+	case yaml.MappingNode:
+		values := make([]*yaml.Node, 0, len(node.Content)/2)
+		for i := 1; i < len(node.Content); i += 2 {
+			values = append(values, node.Content[i])
+		}
+		return values
+	case yaml.SequenceNode:
+		return node.Content
+	default:
+		return nil
+	}
+}
+
+// descendants returns a node and all nodes below it, parents first
+func descendants(m match) []match {
+	result := []match{m}
+	for _, child := range children(m.node) {
+		result = append(result, descendants(match{node: child, parent: m.node})...)
+	}
+	return result
+}
@@ -0,0 +1,155 @@
+package overlay
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Overlay is an OpenAPI Overlay document, a list of actions patching a spec
+type Overlay struct {
+	Overlay string   `yaml:"overlay"`
+	Info    Info     `yaml:"info"`
+	Extends string   `yaml:"extends,omitempty"`
+	Actions []Action `yaml:"actions"`
+}
+
+// Info describes an overlay document
+type Info struct {
+	Title   string `yaml:"title"`
+	Version string `yaml:"version"`
+}
+
+// Action updates or removes the nodes of a spec selected by a JSONPath target
+type Action struct {
+	Target      string    `yaml:"target"`
+	Description string    `yaml:"description,omitempty"`
+	Update      yaml.Node `yaml:"update,omitempty"`
+	Remove      bool      `yaml:"remove,omitempty"`
+}
+
+// Parse parses an overlay document
+func Parse(content []byte) (*Overlay, error) {
+	var overlay Overlay
+	if err := yaml.Unmarshal(content, &overlay); err != nil {
+		return nil, fmt.Errorf("failed to parse overlay: %w", err)
+	}
+	if overlay.Overlay == "" {
+		return nil, fmt.Errorf("invalid overlay: missing overlay version")
+	}
+	for i, action := range overlay.Actions {
+		if _, err := parsePath(action.Target); err != nil {
+			return nil, fmt.Errorf("invalid target of action %d: %w", i, err)
+		}
+		if !action.Remove && action.Update.Kind == 0 {
+			return nil, fmt.Errorf("invalid action %d: neither update nor remove is set", i)
+		}
+	}
+	return &overlay, nil
+}
+
+// Apply applies the actions in order to a YAML or JSON spec and returns the patched spec as YAML,
+// along with the number of nodes each action matched.
+func (o *Overlay) Apply(spec []byte) ([]byte, []int, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(spec, &doc); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse spec: %w", err)
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return nil, nil, fmt.Errorf("failed to parse spec: empty document")
+	}
+	root := doc.Content[0]
+
+	matches := make([]int, len(o.Actions))
+	for i, action := range o.Actions {
+		path, err := parsePath(action.Target)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid target of action %d: %w", i, err)
+		}
+
+		targets := path.selectFrom(root)
+		matches[i] = len(targets)
+		for _, target := range targets {
+			if action.Remove {
+				if target.parent != nil {
+					removeChild(target.parent, target.node)
+				}
+				continue
+			}
+			merge(target.node, &action.Update)
+		}
+	}
+
+	result, err := yaml.Marshal(&doc)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal spec: %w", err)
+	}
+	return result, matches, nil
+}
+
+// merge merges update into target: objects are merged recursively, arrays are concatenated
+// and anything else is replaced by the update value.
+func merge(target, update *yaml.Node) {
+	switch {
+	case target.Kind == yaml.MappingNode && update.Kind == yaml.MappingNode:
+		for i := 0; i+1 < len(update.Content); i += 2 {
+			key, value := update.Content[i], update.Content[i+1]
+			if existing := mappingValue(target, key.Value); existing != nil {
+				merge(existing, value)
+				continue
+			}
+			target.Content = append(target.Content, clone(key), clone(value))
+		}
+	case target.Kind == yaml.SequenceNode && update.Kind == yaml.SequenceNode:
+		for _, item := range update.Content {
+			target.Content = append(target.Content, clone(item))
+		}
+	case target.Kind == yaml.SequenceNode:
+		target.Content = append(target.Content, clone(update))
+	default:
+		*target = *clone(update)
+	}
+}
+
+// removeChild removes a node from its parent mapping or sequence
+func removeChild(parent, child *yaml.Node) {
+	switch parent.Kind {
+	case yaml.MappingNode:
+		for i := 1; i < len(parent.Content); i += 2 {
+			if parent.Content[i] == child {
+				parent.Content = append(parent.Content[:i-1], parent.Content[i+1:]...)
+				return
+			}
+		}
+	case yaml.SequenceNode:
+		for i, item := range parent.Content {
+			if item == child {
+				parent.Content = append(parent.Content[:i], parent.Content[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// mappingValue returns the value of a key in a mapping node
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// clone deep copies a node so that an update applied to several targets is not shared
+func clone(node *yaml.Node) *yaml.Node {
+	copied := *node
+	copied.Content = make([]*yaml.Node, len(node.Content))
+	for i, child := range node.Content {
+		copied.Content[i] = clone(child)
+	}
+	return &copied
+}
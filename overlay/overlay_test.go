@@ -0,0 +1,86 @@
+package overlay
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const spec = `
+openapi: 3.0.3
+paths:
+  /v1/files/retrieve:
+    get:
+      operationId: RetrieveFile
+      parameters:
+        - name: file_id
+          in: query
+        - name: trace
+          in: header
+components:
+  schemas:
+    File:
+      type: object
+      required:
+        - bytes
+      properties:
+        id:
+          type: string
+        bytes:
+          type: integer
+`
+
+const overlayDoc = `
+overlay: 1.0.0
+info:
+  title: sdk fixes
+  version: "1"
+actions:
+  - target: $.components.schemas.File
+    update:
+      required:
+        - id
+      properties:
+        id:
+          description: File ID
+  - target: $.paths['/v1/files/retrieve'].get.parameters[?(@.in == 'header')]
+    remove: true
+  - target: $..operationId
+    update: RetrieveFileOpen
+  - target: $.components.schemas.Bot
+    remove: true
+`
+
+func TestOverlay_Apply(t *testing.T) {
+	o, err := Parse([]byte(overlayDoc))
+	require.NoError(t, err)
+
+	patched, matches, err := o.Apply([]byte(spec))
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 1, 1, 0}, matches)
+
+	var doc map[string]any
+	require.NoError(t, yaml.Unmarshal(patched, &doc))
+
+	file := doc["components"].(map[string]any)["schemas"].(map[string]any)["File"].(map[string]any)
+	require.Equal(t, []any{"bytes", "id"}, file["required"])
+	id := file["properties"].(map[string]any)["id"].(map[string]any)
+	require.Equal(t, "string", id["type"])
+	require.Equal(t, "File ID", id["description"])
+
+	op := doc["paths"].(map[string]any)["/v1/files/retrieve"].(map[string]any)["get"].(map[string]any)
+	require.Equal(t, "RetrieveFileOpen", op["operationId"])
+	require.Len(t, op["parameters"], 1)
+}
+
+func TestOverlay_ParsePath(t *testing.T) {
+	for _, expr := range []string{"$", "$.paths.*", "$..[*]", "$.a[0]['b'][?(@.c)]"} {
+		_, err := parsePath(expr)
+		require.NoError(t, err, expr)
+	}
+	for _, expr := range []string{"paths", "$.a[", "$.a[b]", "$.."} {
+		_, err := parsePath(expr)
+		require.Error(t, err, expr)
+	}
+}
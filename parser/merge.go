@@ -0,0 +1,202 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"golang.org/x/exp/slices"
+)
+
+// Spec is an OpenAPI document and the path it was read from
+type Spec struct {
+	Path    string
+	Content []byte
+}
+
+// mergeSpecs loads several OpenAPI documents and merges their paths and components into one.
+// Servers and security requirements of every document after the first are pushed down to its
+// operations when they differ, so that the merged document keeps a single root client.
+func (p *Parser) mergeSpecs(specs []Spec) (*openapi3.T, error) {
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("no OpenAPI document to parse")
+	}
+
+	var merged *openapi3.T
+	operationSources := make(map[string]string)
+	p.sources = make(map[*openapi3.Operation]string)
+	for _, spec := range specs {
+		doc, err := LoadSpec(spec)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s: %w", spec.Path, err)
+		}
+
+		// Tag operations with their source document and check operation ids are unique
+		for _, path := range doc.Paths.InMatchingOrder() {
+			for _, op := range doc.Paths.Value(path).Operations() {
+				if op.OperationID != "" {
+					if source, ok := operationSources[op.OperationID]; ok {
+						return nil, fmt.Errorf("operation %s is declared in both %s and %s", op.OperationID, source, spec.Path)
+					}
+					operationSources[op.OperationID] = spec.Path
+				}
+				p.sources[op] = spec.Path
+			}
+		}
+
+		if merged == nil {
+			merged = doc
+			if merged.Components == nil {
+				merged.Components = &openapi3.Components{}
+			}
+			continue
+		}
+		if err := mergeDocument(merged, doc, spec.Path); err != nil {
+			return nil, err
+		}
+	}
+	return merged, nil
+}
+
+// mergeDocument merges doc into merged
+func mergeDocument(merged, doc *openapi3.T, source string) error {
+	servers := serverURLs(doc.Servers)
+	overrideServers := len(servers) > 0 && !slices.Equal(servers, serverURLs(merged.Servers))
+	overrideSecurity := doc.Security != nil && !equalJSON(doc.Security, merged.Security)
+
+	// Merge paths, the same method of a path must not be declared twice
+	for _, path := range doc.Paths.InMatchingOrder() {
+		pathItem := doc.Paths.Value(path)
+		for method, op := range pathItem.Operations() {
+			if overrideServers && op.Servers == nil && len(pathItem.Servers) == 0 {
+				op.Servers = &doc.Servers
+			}
+			if overrideSecurity && op.Security == nil {
+				op.Security = &doc.Security
+			}
+
+			existing := merged.Paths.Value(path)
+			if existing == nil {
+				existing = &openapi3.PathItem{Parameters: pathItem.Parameters, Servers: pathItem.Servers}
+				merged.Paths.Set(path, existing)
+			} else if !equalJSON(existing.Parameters, pathItem.Parameters) {
+				return fmt.Errorf("path %s declares different parameters in %s", path, source)
+			}
+			// The path level servers of the merged path are the ones of another document
+			if op.Servers == nil && !equalJSON(existing.Servers, pathItem.Servers) {
+				servers := pathItem.Servers
+				op.Servers = &servers
+			}
+			if existing.GetOperation(method) != nil {
+				return fmt.Errorf("operation %s %s of %s is already declared", method, path, source)
+			}
+			existing.SetOperation(method, op)
+		}
+	}
+
+	// Merge components, the same name must refer to the same shape
+	if doc.Components != nil {
+		if err := mergeComponents(merged.Components, doc.Components, source); err != nil {
+			return err
+		}
+	}
+
+	// Merge the remaining document level declarations
+	for _, server := range doc.Servers {
+		if !slices.Contains(serverURLs(merged.Servers), server.URL) {
+			merged.Servers = append(merged.Servers, server)
+		}
+	}
+	for _, tag := range doc.Tags {
+		if merged.Tags.Get(tag.Name) == nil {
+			merged.Tags = append(merged.Tags, tag)
+		}
+	}
+	return nil
+}
+
+// mergeComponents merges the components of a document into the merged components
+func mergeComponents(merged, components *openapi3.Components, source string) error {
+	if merged.Schemas == nil {
+		merged.Schemas = openapi3.Schemas{}
+	}
+	if merged.Parameters == nil {
+		merged.Parameters = openapi3.ParametersMap{}
+	}
+	if merged.RequestBodies == nil {
+		merged.RequestBodies = openapi3.RequestBodies{}
+	}
+	if merged.Responses == nil {
+		merged.Responses = openapi3.ResponseBodies{}
+	}
+	if merged.SecuritySchemes == nil {
+		merged.SecuritySchemes = openapi3.SecuritySchemes{}
+	}
+
+	for _, name := range sortedKeys(components.Schemas) {
+		if err := mergeComponent(merged.Schemas, components.Schemas, name, "schema", source, func(ref *openapi3.SchemaRef) any { return ref.Value }); err != nil {
+			return err
+		}
+	}
+	for _, name := range sortedKeys(components.Parameters) {
+		if err := mergeComponent(merged.Parameters, components.Parameters, name, "parameter", source, func(ref *openapi3.ParameterRef) any { return ref.Value }); err != nil {
+			return err
+		}
+	}
+	for _, name := range sortedKeys(components.RequestBodies) {
+		if err := mergeComponent(merged.RequestBodies, components.RequestBodies, name, "request body", source, func(ref *openapi3.RequestBodyRef) any { return ref.Value }); err != nil {
+			return err
+		}
+	}
+	for _, name := range sortedKeys(components.Responses) {
+		if err := mergeComponent(merged.Responses, components.Responses, name, "response", source, func(ref *openapi3.ResponseRef) any { return ref.Value }); err != nil {
+			return err
+		}
+	}
+	for _, name := range sortedKeys(components.SecuritySchemes) {
+		if err := mergeComponent(merged.SecuritySchemes, components.SecuritySchemes, name, "security scheme", source, func(ref *openapi3.SecuritySchemeRef) any { return ref.Value }); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeComponent adds a named component to merged, failing when one with a different shape has the same name
+func mergeComponent[V any](merged, components map[string]V, name, kind, source string, shape func(V) any) error {
+	component := components[name]
+	if existing, ok := merged[name]; ok {
+		if !equalJSON(shape(existing), shape(component)) {
+			return fmt.Errorf("%s %s of %s conflicts with an existing %s with a different shape", kind, name, source, kind)
+		}
+		return nil
+	}
+	merged[name] = component
+	return nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// equalJSON compares two values by their JSON representation
+func equalJSON(a, b any) bool {
+	aJSON, aErr := json.Marshal(a)
+	bJSON, bErr := json.Marshal(b)
+	return aErr == nil && bErr == nil && string(aJSON) == string(bJSON)
+}
+
+func serverURLs(servers openapi3.Servers) []string {
+	urls := make([]string, 0, len(servers))
+	for _, server := range servers {
+		if server != nil {
+			urls = append(urls, server.URL)
+		}
+	}
+	return urls
+}
@@ -3,8 +3,12 @@ package parser
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
 	"sort"
 	"strings"
+	"unicode"
 
 	"github.com/coze-dev/coze-sdk-gen/util"
 	"github.com/getkin/kin-openapi/openapi3"
@@ -49,14 +53,26 @@ const (
 	PrimitiveBool    PrimitiveKind = "bool"
 	PrimitiveBinary  PrimitiveKind = "binary"
 	PrimitiveUnknown PrimitiveKind = ""
+
+	// Formats of integers and strings
+	PrimitiveInt64         PrimitiveKind = "int64"
+	PrimitiveDateTime      PrimitiveKind = "date-time"
+	PrimitiveDate          PrimitiveKind = "date"
+	PrimitiveUUID          PrimitiveKind = "uuid"
+	PrimitiveURI           PrimitiveKind = "uri"
+	PrimitiveByte          PrimitiveKind = "byte"    // base64 encoded bytes
+	PrimitiveDecimal       PrimitiveKind = "decimal" // decimal number serialized as a string
+	PrimitiveUnixTimestamp PrimitiveKind = "unix-timestamp"
+	PrimitiveInt64String   PrimitiveKind = "int64-string" // int64 serialized as a string
 )
 
 // Ty represents a type in the schema
 type Ty struct {
-	Name        string `json:"name,omitempty"`
-	Description string `json:"description,omitempty"`
-	Kind        TyKind `json:"kind"`
-	Module      string `json:"module,omitempty"` // The module this type belongs to
+	Name         string       `json:"name,omitempty"`
+	Description  string       `json:"description,omitempty"`
+	Descriptions Descriptions `json:"descriptions,omitempty"`
+	Kind         TyKind       `json:"kind"`
+	Module       string       `json:"module,omitempty"` // The module this type belongs to
 
 	// For primitive types
 	PrimitiveKind PrimitiveKind `json:"primitive_kind,omitempty"`
@@ -73,17 +89,180 @@ type Ty struct {
 
 	// Metadata
 	IsNamed bool `json:"is_named,omitempty"` // Whether this is a named type (from components)
+
+	// Validation keywords of the schema
+	Constraints *Constraints `json:"constraints,omitempty"`
+
+	Examples []Example `json:"examples,omitempty"`
+
+	Deprecation
 }
 
 // TyField represents a field in an object type
 type TyField struct {
-	Name        string `json:"name"`
-	Description string `json:"description,omitempty"`
-	Type        *Ty    `json:"type"`
-	Required    bool   `json:"required,omitempty"`
-	Default     string `json:"default,omitempty"`
+	Name         string       `json:"name"`
+	Description  string       `json:"description,omitempty"`
+	Descriptions Descriptions `json:"descriptions,omitempty"`
+	Type         *Ty          `json:"type"`
+	Required     bool         `json:"required,omitempty"`
+	Default      string       `json:"default,omitempty"`
+
+	// ReadOnly fields are only sent by the server, WriteOnly fields only by the client
+	ReadOnly  bool `json:"read_only,omitempty"`
+	WriteOnly bool `json:"write_only,omitempty"`
+
+	// DefaultValue is the default of the schema as decoded from the spec, Default is a literal
+	// of the generated language configured through ChangeFields and takes precedence
+	DefaultValue any `json:"default_value,omitempty"`
+
+	// Validation keywords of the field or parameter schema, including referenced ones
+	Constraints *Constraints `json:"constraints,omitempty"`
+
+	Examples []Example `json:"examples,omitempty"`
+
+	Deprecation
+
+	// Serialization of parameters, resolved to the OpenAPI defaults of their location
+	Style         ParamStyle `json:"style,omitempty"`
+	Explode       bool       `json:"explode,omitempty"`
+	AllowReserved bool       `json:"allow_reserved,omitempty"`
+}
+
+// Constraints holds the validation keywords of a schema, unset keywords are nil
+type Constraints struct {
+	Minimum          *float64 `json:"minimum,omitempty"`
+	Maximum          *float64 `json:"maximum,omitempty"`
+	ExclusiveMinimum bool     `json:"exclusive_minimum,omitempty"`
+	ExclusiveMaximum bool     `json:"exclusive_maximum,omitempty"`
+	MultipleOf       *float64 `json:"multiple_of,omitempty"`
+	MinLength        *uint64  `json:"min_length,omitempty"`
+	MaxLength        *uint64  `json:"max_length,omitempty"`
+	Pattern          string   `json:"pattern,omitempty"`
+	MinItems         *uint64  `json:"min_items,omitempty"`
+	MaxItems         *uint64  `json:"max_items,omitempty"`
+}
+
+// getConstraints returns the validation keywords of a schema, or nil if it has none
+func getConstraints(schema *openapi3.Schema) *Constraints {
+	constraints := &Constraints{
+		Minimum:          schema.Min,
+		Maximum:          schema.Max,
+		ExclusiveMinimum: schema.Min != nil && schema.ExclusiveMin,
+		ExclusiveMaximum: schema.Max != nil && schema.ExclusiveMax,
+		MultipleOf:       schema.MultipleOf,
+		MaxLength:        schema.MaxLength,
+		Pattern:          schema.Pattern,
+		MaxItems:         schema.MaxItems,
+	}
+	// minLength and minItems default to 0, which is no constraint
+	if schema.MinLength > 0 {
+		minLength := schema.MinLength
+		constraints.MinLength = &minLength
+	}
+	if schema.MinItems > 0 {
+		minItems := schema.MinItems
+		constraints.MinItems = &minItems
+	}
+
+	if *constraints == (Constraints{}) {
+		return nil
+	}
+	return constraints
+}
+
+// Example is an example value of a schema, parameter or payload. Examples declared with example
+// have no name.
+type Example struct {
+	Name    string `json:"name,omitempty"`
+	Summary string `json:"summary,omitempty"`
+	Value   any    `json:"value"`
+}
+
+// getExamples collects the example and the named examples of a schema, parameter or media type,
+// sorted by name. Examples with an external value are skipped.
+func getExamples(example any, examples openapi3.Examples) []Example {
+	var result []Example
+	if example != nil {
+		result = append(result, Example{Value: example})
+	}
+	for _, name := range sortedKeys(examples) {
+		ref := examples[name]
+		if ref == nil || ref.Value == nil || ref.Value.Value == nil {
+			continue
+		}
+		result = append(result, Example{Name: name, Summary: ref.Value.Summary, Value: ref.Value.Value})
+	}
+	return result
+}
+
+// Description languages
+const (
+	LangEn = "en"
+	LangZh = "zh"
+)
+
+// Descriptions holds the texts of a description by language. Description keeps the text
+// picked regardless of the language, the title if there is one.
+type Descriptions map[string]string
+
+// getDescriptions sorts texts into languages by their script, the first text of a language
+// taking precedence. The x-coze-i18n extension maps languages to explicit texts.
+func getDescriptions(texts []string, extensions map[string]interface{}) (Descriptions, error) {
+	descriptions := make(Descriptions)
+	for _, text := range texts {
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		lang := LangEn
+		if strings.IndexFunc(text, func(r rune) bool { return unicode.Is(unicode.Han, r) }) >= 0 {
+			lang = LangZh
+		}
+		if _, ok := descriptions[lang]; !ok {
+			descriptions[lang] = text
+		}
+	}
+
+	if ext, ok := extensions["x-coze-i18n"]; ok && ext != nil {
+		texts, ok := ext.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("x-coze-i18n must be a map of language to text, got %v", ext)
+		}
+		for lang, text := range texts {
+			str, ok := text.(string)
+			if !ok {
+				return nil, fmt.Errorf("x-coze-i18n.%s must be a string, got %v", lang, text)
+			}
+			descriptions[lang] = str
+		}
+	}
+
+	if len(descriptions) == 0 {
+		return nil, nil
+	}
+	return descriptions, nil
 }
 
+// Deprecation marks an operation, type or field as deprecated, from deprecated: true and
+// the x-coze-deprecated-message and x-coze-deprecated-replacement extensions
+type Deprecation struct {
+	Deprecated            bool   `json:"deprecated,omitempty"`
+	DeprecatedMessage     string `json:"deprecated_message,omitempty"`
+	DeprecatedReplacement string `json:"deprecated_replacement,omitempty"` // e.g. the operation to use instead
+}
+
+// ParamStyle represents how a parameter value is serialized
+type ParamStyle string
+
+const (
+	ParamStyleForm           ParamStyle = "form"
+	ParamStyleSimple         ParamStyle = "simple"
+	ParamStyleSpaceDelimited ParamStyle = "spaceDelimited"
+	ParamStylePipeDelimited  ParamStyle = "pipeDelimited"
+	ParamStyleDeepObject     ParamStyle = "deepObject"
+	ParamStyleMatrix         ParamStyle = "matrix"
+	ParamStyleLabel          ParamStyle = "label"
+)
+
 type TyEnumValue struct {
 	Name string      `json:"name,omitempty"`
 	Val  interface{} `json:"val"`
@@ -97,24 +276,66 @@ const (
 	ContentTypeFile ContentType = "file"
 )
 
+// ResponseKind represents how a response body is consumed
+type ResponseKind string
+
+const (
+	ResponseKindJson   ResponseKind = "json"
+	ResponseKindBinary ResponseKind = "binary" // octet-stream, audio, image and video downloads
+	ResponseKindText   ResponseKind = "text"   // plain text
+)
+
+// BodyStyle represents how a request body is exposed to SDK callers
+type BodyStyle string
+
+const (
+	BodyStyleFlatten BodyStyle = "flatten" // every body field becomes a keyword argument
+	BodyStyleModel   BodyStyle = "model"   // the whole body is passed as a single typed model
+	BodyStyleBoth    BodyStyle = "both"    // both forms are accepted
+)
+
 // HttpHandler represents an API operation
 type HttpHandler struct {
-	Name        string `json:"name"`
-	Description string `json:"description,omitempty"`
-	Path        string `json:"path"`
-	Method      string `json:"method"`
+	Name         string       `json:"name"`
+	Description  string       `json:"description,omitempty"`
+	Descriptions Descriptions `json:"descriptions,omitempty"`
+	Path         string       `json:"path"`
+	Method       string       `json:"method"`
 
 	// Content Type
 	ContentType ContentType `json:"content_type"`
 
+	// How the request body is exposed, from x-coze-body-style or configuration
+	BodyStyle BodyStyle `json:"body_style,omitempty"`
+
 	// Parameters split by location
 	HeaderParams []TyField `json:"header_params,omitempty"`
 	PathParams   []TyField `json:"path_params,omitempty"`
 	QueryParams  []TyField `json:"query_params,omitempty"`
+	CookieParams []TyField `json:"cookie_params,omitempty"`
 
 	// Request and Response
 	RequestBody  *Ty `json:"request_body"`
 	ResponseBody *Ty `json:"response_body"`
+
+	// Response representation, ResponseBody is only set for JSON responses
+	ResponseKind        ResponseKind `json:"response_kind,omitempty"`
+	ResponseContentType string       `json:"response_content_type,omitempty"`
+
+	// Alternative security requirements, any one of them authorizes the request
+	Security []SecurityRequirement `json:"security,omitempty"`
+
+	// Servers overriding the document level servers for this operation
+	Servers []Server `json:"servers,omitempty"`
+
+	// Path of the spec the operation is declared in, set when parsing several specs
+	Source string `json:"source,omitempty"`
+
+	// Examples of the request body and of the response body
+	RequestExamples  []Example `json:"request_examples,omitempty"`
+	ResponseExamples []Example `json:"response_examples,omitempty"`
+
+	Deprecation
 }
 
 // Default pagination parameter candidates
@@ -245,6 +466,8 @@ type Module struct {
 type ModuleConfig struct {
 	TypeModuleMap                 map[string]string                        `json:"type_module_map"`                   // Maps type names to module names
 	GenerateUnnamedResponseType   func(*HttpHandler) (string, bool)        `json:"generate_unnamed_response_type"`    // if response type is unnamed, will auto gen named
+	GenerateUnnamedRequestType    func(*HttpHandler) (string, bool)        `json:"generate_unnamed_request_type"`     // if request type is unnamed and not flattened, will auto gen named
+	BodyStyles                    map[string]BodyStyle                     `json:"body_styles"`                       // change body style for http handler, key is operation id
 	ChangeHttpHandlerResponseType map[string]string                        `json:"change_http_handler_response_type"` // change response type for http handler
 	RenameTypes                   map[string]string                        `json:"rename_types"`                      // rename types, key is old name, value is new name
 	RenameHandlers                map[string]string                        `json:"rename_handlers"`                   // rename http handlers, key is old name, value is new name
@@ -254,10 +477,13 @@ type ModuleConfig struct {
 
 // Parser handles OpenAPI parsing with the new schema design
 type Parser struct {
-	namedTypes map[string]*Ty     // All types indexed by name
-	modules    map[string]*Module // All modules
-	config     *ModuleConfig      // Module configuration
-	doc        *openapi3.T        // The OpenAPI document
+	namedTypes      map[string]*Ty                 // All types indexed by name
+	modules         map[string]*Module             // All modules
+	securitySchemes map[string]*SecurityScheme     // All security schemes indexed by name
+	servers         []Server                       // Document level servers
+	config          *ModuleConfig                  // Module configuration
+	doc             *openapi3.T                    // The OpenAPI document
+	sources         map[*openapi3.Operation]string // Source spec of each operation when merging specs
 }
 
 // NewParser creates a new Parser2 instance
@@ -267,9 +493,10 @@ func NewParser(config *ModuleConfig) (*Parser, error) {
 	}
 
 	return &Parser{
-		namedTypes: make(map[string]*Ty),
-		modules:    make(map[string]*Module),
-		config:     config,
+		namedTypes:      make(map[string]*Ty),
+		modules:         make(map[string]*Module),
+		securitySchemes: make(map[string]*SecurityScheme),
+		config:          config,
 	}, nil
 }
 
@@ -298,12 +525,41 @@ func (p *Parser) generateUnnamedResponseTypes() error {
 
 			module.HttpHandlers[i].ResponseBody.Name = name
 			module.HttpHandlers[i].ResponseBody.IsNamed = true
+			module.HttpHandlers[i].ResponseBody.Module = module.Name
 			module.Types = append(module.Types, module.HttpHandlers[i].ResponseBody)
 		}
 	}
 	return nil
 }
 
+// generateUnnamedRequestTypes generates names for unnamed request types of handlers
+// whose body is not flattened, so the body can be passed as a single model
+func (p *Parser) generateUnnamedRequestTypes() error {
+	if p.config.GenerateUnnamedRequestType == nil {
+		return nil
+	}
+
+	for _, module := range p.modules {
+		for i := range module.HttpHandlers {
+			handler := &module.HttpHandlers[i]
+			if handler.RequestBody == nil || handler.RequestBody.IsNamed || handler.BodyStyle == BodyStyleFlatten {
+				continue
+			}
+
+			name, gen := p.config.GenerateUnnamedRequestType(handler)
+			if !gen {
+				continue
+			}
+
+			handler.RequestBody.Name = name
+			handler.RequestBody.IsNamed = true
+			handler.RequestBody.Module = module.Name
+			module.Types = append(module.Types, handler.RequestBody)
+		}
+	}
+	return nil
+}
+
 // changeFieldRequirements changes field requirements based on configuration
 func (p *Parser) changeFieldRequirements() error {
 	if len(p.config.ChangeFields) == 0 {
@@ -458,12 +714,41 @@ func (p *Parser) renameHandlers() error {
 
 // ParseOpenAPI parses an OpenAPI document and returns modules
 func (p *Parser) ParseOpenAPI(yamlContent []byte) (map[string]*Module, error) {
-	// Parse OpenAPI document
+	return p.ParseOpenAPIWithPath(yamlContent, "")
+}
+
+// ParseOpenAPIWithPath parses an OpenAPI document located at specPath and returns modules.
+// References to other files are resolved relative to specPath.
+func (p *Parser) ParseOpenAPIWithPath(yamlContent []byte, specPath string) (map[string]*Module, error) {
+	doc, err := LoadSpec(Spec{Path: specPath, Content: yamlContent})
+	if err != nil {
+		return nil, err
+	}
+	return p.parseDocument(doc)
+}
+
+// ParseOpenAPISpecs parses several OpenAPI documents, merged into a single document, and returns modules
+func (p *Parser) ParseOpenAPISpecs(specs []Spec) (map[string]*Module, error) {
+	doc, err := p.mergeSpecs(specs)
+	if err != nil {
+		return nil, err
+	}
+	return p.parseDocument(doc)
+}
+
+// LoadSpec loads an OpenAPI document, references to other files are resolved relative to its path
+func LoadSpec(spec Spec) (*openapi3.T, error) {
 	loader := openapi3.NewLoader()
-	doc, err := loader.LoadFromData(yamlContent)
+	loader.IsExternalRefsAllowed = true
+	doc, err := loader.LoadFromDataWithPath(spec.Content, &url.URL{Path: filepath.ToSlash(spec.Path)})
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse OpenAPI document: %w", err)
 	}
+	return doc, nil
+}
+
+// parseDocument converts a loaded OpenAPI document into modules
+func (p *Parser) parseDocument(doc *openapi3.T) (map[string]*Module, error) {
 	p.doc = doc
 
 	// Process all named types from components
@@ -471,6 +756,16 @@ func (p *Parser) ParseOpenAPI(yamlContent []byte) (map[string]*Module, error) {
 		return nil, err
 	}
 
+	// Process all security schemes from components
+	if err := p.processSecuritySchemes(); err != nil {
+		return nil, err
+	}
+
+	// Process document level servers
+	if err := p.processServers(); err != nil {
+		return nil, err
+	}
+
 	// Process all operations and their types
 	if err := p.processOperations(); err != nil {
 		return nil, err
@@ -481,6 +776,11 @@ func (p *Parser) ParseOpenAPI(yamlContent []byte) (map[string]*Module, error) {
 		return nil, err
 	}
 
+	// Generate names for unnamed request types
+	if err := p.generateUnnamedRequestTypes(); err != nil {
+		return nil, err
+	}
+
 	// Change field requirements based on configuration
 	if err := p.changeFieldRequirements(); err != nil {
 		return nil, err
@@ -543,11 +843,20 @@ func (p *Parser) processNamedTypes() error {
 func (p *Parser) processOperations() error {
 	for path, pathItem := range p.doc.Paths.Map() {
 		for method, op := range pathItem.Operations() {
-			handler, err := p.convertOperation(path, method, op)
+			handler, err := p.convertOperation(path, method, pathItem, op)
 			if err != nil {
 				return fmt.Errorf("failed to convert operation %s: %w", op.OperationID, err)
 			}
 
+			// Operation servers take precedence over path item servers
+			servers := pathItem.Servers
+			if op.Servers != nil {
+				servers = *op.Servers
+			}
+			if handler.Servers, err = convertServers(servers); err != nil {
+				return fmt.Errorf("failed to convert servers of operation %s: %w", op.OperationID, err)
+			}
+
 			// Get or create module
 			moduleName := "default"
 			if len(op.Tags) > 0 {
@@ -575,14 +884,19 @@ func (p *Parser) convertSchema(schema *openapi3.SchemaRef, name string, isNamed
 		return nil, fmt.Errorf("nil schema value. v=%v", marshal(schema.Value))
 	}
 
-	// If it's a reference and we've already processed it, return the existing type
+	// If it's a reference and we've already processed it, return the existing type.
+	// The loader has already resolved the target into the value, so references that
+	// do not point to a component schema (e.g. nested properties) are converted in place.
 	if schema.Ref != "" {
-		refName := getRefName(schema.Ref)
+		resolved := &openapi3.SchemaRef{Value: schema.Value}
+		refName, ok := getSchemaRefName(schema.Ref)
+		if !ok {
+			return p.convertSchema(resolved, name, isNamed)
+		}
 		if existing := p.namedTypes[refName]; existing != nil {
 			return existing, nil
-		} else {
-			return p.convertSchema(p.doc.Components.Schemas[refName], refName, true)
 		}
+		return p.convertSchema(resolved, refName, true)
 	}
 
 	ty := &Ty{
@@ -590,6 +904,20 @@ func (p *Parser) convertSchema(schema *openapi3.SchemaRef, name string, isNamed
 		IsNamed:     isNamed,
 		Description: util.Choose(schema.Value.Title != "", schema.Value.Title, schema.Value.Description),
 	}
+	descriptions, err := getDescriptions([]string{schema.Value.Title, schema.Value.Description}, schema.Value.Extensions)
+	if err != nil {
+		return nil, fmt.Errorf("schema %s: %w", name, err)
+	}
+	ty.Descriptions = descriptions
+	ty.Constraints = getConstraints(schema.Value)
+	ty.Examples = getExamples(schema.Value.Example, nil)
+	if isNamed {
+		deprecation, err := getDeprecation(schema.Value.Deprecated, schema.Value.Extensions)
+		if err != nil {
+			return nil, fmt.Errorf("schema %s: %w", name, err)
+		}
+		ty.Deprecation = deprecation
+	}
 
 	// Check if it's a map type first
 	if schema.Value.AdditionalProperties.Schema != nil {
@@ -646,7 +974,12 @@ func (p *Parser) convertSchema(schema *openapi3.SchemaRef, name string, isNamed
 
 		default:
 			ty.Kind = TyKindPrimitive
-			ty.PrimitiveKind = p.convertPrimitiveType(*schema.Value.Type, schema.Value.Format)
+			// x-coze-format refines the format for what OpenAPI has no format for, e.g. unix-timestamp
+			format := schema.Value.Format
+			if cozeFormat, ok := schema.Value.Extensions["x-coze-format"].(string); ok {
+				format = cozeFormat
+			}
+			ty.PrimitiveKind = p.convertPrimitiveType(*schema.Value.Type, format)
 			if schema.Value.Enum != nil {
 				for _, val := range schema.Value.Enum {
 					ty.EnumValues = append(ty.EnumValues, TyEnumValue{Name: "", Val: val})
@@ -665,6 +998,7 @@ func (p *Parser) convertSchema(schema *openapi3.SchemaRef, name string, isNamed
 		p.namedTypes[name] = ty
 	} else {
 		ty.Description = ""
+		ty.Descriptions = nil
 	}
 
 	return ty, nil
@@ -685,26 +1019,67 @@ func (p *Parser) convertField(name string, schema *openapi3.SchemaRef, required
 		}
 	}
 
+	// A referenced schema carries the deprecation of its type, not of the field
+	var deprecation Deprecation
+	if schema.Ref == "" {
+		if deprecation, err = getDeprecation(schema.Value.Deprecated, schema.Value.Extensions); err != nil {
+			return nil, err
+		}
+	}
+
+	descriptions, err := getDescriptions([]string{schema.Value.Title, schema.Value.Description}, schema.Value.Extensions)
+	if err != nil {
+		return nil, fmt.Errorf("field %s: %w", name, err)
+	}
+
 	return &TyField{
-		Name:        name,
-		Description: util.Choose(schema.Value.Title != "", schema.Value.Title, schema.Value.Description),
-		Type:        fieldType,
-		Required:    isRequired,
+		Name:         name,
+		Description:  util.Choose(schema.Value.Title != "", schema.Value.Title, schema.Value.Description),
+		Descriptions: descriptions,
+		Type:         fieldType,
+		Required:     isRequired,
+		ReadOnly:     schema.Value.ReadOnly,
+		WriteOnly:    schema.Value.WriteOnly,
+		DefaultValue: schema.Value.Default,
+		Examples:     getExamples(schema.Value.Example, nil),
+		Constraints:  getConstraints(schema.Value),
+		Deprecation:  deprecation,
 	}, nil
 }
 
 // convertOperation converts an OpenAPI operation to our HttpHandler
-func (p *Parser) convertOperation(path, method string, op *openapi3.Operation) (*HttpHandler, error) {
+func (p *Parser) convertOperation(path, method string, pathItem *openapi3.PathItem, op *openapi3.Operation) (*HttpHandler, error) {
 	handler := &HttpHandler{
 		Name:        op.OperationID,
 		Description: op.Description,
 		Path:        path,
 		Method:      method,
+		Source:      p.sources[op],
 		ContentType: ContentTypeJson, // Default to JSON
 	}
 
-	// Convert parameters
-	for _, param := range op.Parameters {
+	bodyStyle, err := p.getBodyStyle(op)
+	if err != nil {
+		return nil, err
+	}
+	handler.BodyStyle = bodyStyle
+
+	if handler.Deprecation, err = getDeprecation(op.Deprecated, op.Extensions); err != nil {
+		return nil, err
+	}
+	// The one-line summary only stands in for a missing description
+	if handler.Descriptions, err = getDescriptions([]string{op.Description, op.Summary}, op.Extensions); err != nil {
+		return nil, err
+	}
+
+	security, err := p.convertSecurity(op)
+	if err != nil {
+		return nil, err
+	}
+	handler.Security = security
+
+	// Convert parameters, including the ones shared by all operations of the path
+	for _, param := range mergeParameters(pathItem.Parameters, op.Parameters) {
 		if param.Value == nil {
 			continue
 		}
@@ -715,10 +1090,30 @@ func (p *Parser) convertOperation(path, method string, op *openapi3.Operation) (
 		}
 
 		parameter := TyField{
-			Name:        param.Value.Name,
-			Description: param.Value.Description,
-			Required:    param.Value.Required,
-			Type:        paramType,
+			Name:          param.Value.Name,
+			Description:   param.Value.Description,
+			Required:      param.Value.Required,
+			Type:          paramType,
+			AllowReserved: param.Value.AllowReserved,
+		}
+		parameter.Examples = getExamples(param.Value.Example, param.Value.Examples)
+		var title string
+		if param.Value.Schema != nil && param.Value.Schema.Value != nil {
+			title = param.Value.Schema.Value.Title
+		}
+		if parameter.Descriptions, err = getDescriptions([]string{title, param.Value.Description}, param.Value.Extensions); err != nil {
+			return nil, fmt.Errorf("parameter %s: %w", param.Value.Name, err)
+		}
+		if param.Value.Schema != nil && param.Value.Schema.Value != nil {
+			parameter.DefaultValue = param.Value.Schema.Value.Default
+			parameter.Constraints = getConstraints(param.Value.Schema.Value)
+			if len(parameter.Examples) == 0 {
+				parameter.Examples = getExamples(param.Value.Schema.Value.Example, nil)
+			}
+		}
+		parameter.Style, parameter.Explode = getParamStyle(param.Value)
+		if parameter.Deprecation, err = getDeprecation(param.Value.Deprecated, param.Value.Extensions); err != nil {
+			return nil, fmt.Errorf("parameter %s: %w", param.Value.Name, err)
 		}
 
 		switch param.Value.In {
@@ -728,9 +1123,15 @@ func (p *Parser) convertOperation(path, method string, op *openapi3.Operation) (
 			handler.PathParams = append(handler.PathParams, parameter)
 		case "query":
 			handler.QueryParams = append(handler.QueryParams, parameter)
+		case "cookie":
+			handler.CookieParams = append(handler.CookieParams, parameter)
 		}
 	}
 
+	if err := checkPathParams(path, handler.PathParams); err != nil {
+		return nil, err
+	}
+
 	// Convert request body
 	if op.RequestBody != nil && op.RequestBody.Value != nil {
 		for contentType, content := range op.RequestBody.Value.Content {
@@ -740,6 +1141,7 @@ func (p *Parser) convertOperation(path, method string, op *openapi3.Operation) (
 					return nil, fmt.Errorf("failed to convert request body schema: %w", err)
 				}
 				handler.RequestBody = requestType
+				handler.RequestExamples = getExamples(content.Example, content.Examples)
 
 				// Set content type based on request body content type
 				switch contentType {
@@ -755,14 +1157,19 @@ func (p *Parser) convertOperation(path, method string, op *openapi3.Operation) (
 
 	// Convert response body
 	if response, ok := op.Responses.Map()["200"]; ok && response.Value.Content != nil {
-		for _, content := range response.Value.Content {
-			if content.Schema != nil {
-				responseType, err := p.convertSchema(content.Schema, "", false)
-				if err != nil {
-					return nil, fmt.Errorf("failed to convert response schema: %w", err)
-				}
-				handler.ResponseBody = responseType
-				break
+		contentType, kind := selectResponseContentType(response.Value.Content)
+		handler.ResponseKind = kind
+		handler.ResponseContentType = contentType
+
+		if content := response.Value.Content[contentType]; kind == ResponseKindJson && content.Schema != nil {
+			responseType, err := p.convertSchema(content.Schema, "", false)
+			if err != nil {
+				return nil, fmt.Errorf("failed to convert response schema: %w", err)
+			}
+			handler.ResponseBody = responseType
+			handler.ResponseExamples = getExamples(content.Example, content.Examples)
+			if len(handler.ResponseExamples) == 0 {
+				handler.ResponseExamples = responseType.Examples
 			}
 		}
 	}
@@ -770,6 +1177,182 @@ func (p *Parser) convertOperation(path, method string, op *openapi3.Operation) (
 	return handler, nil
 }
 
+// mergeParameters merges the parameters declared on a path item with the ones of an operation.
+// Operation parameters override path item parameters with the same name and location.
+func mergeParameters(pathParams, opParams openapi3.Parameters) openapi3.Parameters {
+	key := func(param *openapi3.ParameterRef) string {
+		return param.Value.In + ":" + param.Value.Name
+	}
+
+	overrides := make(map[string]*openapi3.ParameterRef)
+	for _, param := range opParams {
+		if param != nil && param.Value != nil {
+			overrides[key(param)] = param
+		}
+	}
+
+	merged := make(openapi3.Parameters, 0, len(pathParams)+len(opParams))
+	seen := make(map[string]bool)
+	for _, param := range pathParams {
+		if param == nil || param.Value == nil {
+			continue
+		}
+		if override, ok := overrides[key(param)]; ok {
+			param = override
+		}
+		seen[key(param)] = true
+		merged = append(merged, param)
+	}
+	for _, param := range opParams {
+		if param == nil || param.Value == nil || seen[key(param)] {
+			continue
+		}
+		merged = append(merged, param)
+	}
+	return merged
+}
+
+// pathPlaceholderRegexp matches the {placeholder} segments of a path template
+var pathPlaceholderRegexp = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// getPathPlaceholders returns the placeholder names of a path template in order
+func getPathPlaceholders(path string) []string {
+	var names []string
+	for _, match := range pathPlaceholderRegexp.FindAllStringSubmatch(path, -1) {
+		names = append(names, match[1])
+	}
+	return names
+}
+
+// ReplacePathPlaceholders rewrites every {placeholder} of a path template with the result of replace
+func ReplacePathPlaceholders(path string, replace func(name string) string) string {
+	return pathPlaceholderRegexp.ReplaceAllStringFunc(path, func(placeholder string) string {
+		return replace(placeholder[1 : len(placeholder)-1])
+	})
+}
+
+// checkPathParams checks that every placeholder of a path template has a matching path parameter
+func checkPathParams(path string, params []TyField) error {
+	declared := make(map[string]bool)
+	for _, param := range params {
+		declared[param.Name] = true
+	}
+	for _, name := range getPathPlaceholders(path) {
+		if !declared[name] {
+			return fmt.Errorf("path %s has placeholder {%s} without a matching path parameter", path, name)
+		}
+	}
+	return nil
+}
+
+// getParamStyle returns the style and explode of a parameter, applying the OpenAPI defaults:
+// form with explode for query and cookie parameters, simple without explode for path and header parameters
+func getParamStyle(param *openapi3.Parameter) (ParamStyle, bool) {
+	style := ParamStyle(param.Style)
+	if style == "" {
+		switch param.In {
+		case "query", "cookie":
+			style = ParamStyleForm
+		default:
+			style = ParamStyleSimple
+		}
+	}
+
+	explode := style == ParamStyleForm
+	if param.Explode != nil {
+		explode = *param.Explode
+	}
+	return style, explode
+}
+
+// selectResponseContentType picks the response content type to generate for, JSON is preferred
+// when an operation offers several representations
+func selectResponseContentType(content openapi3.Content) (string, ResponseKind) {
+	contentTypes := make([]string, 0, len(content))
+	for contentType := range content {
+		contentTypes = append(contentTypes, contentType)
+	}
+	sort.Strings(contentTypes)
+
+	var selected string
+	var selectedKind ResponseKind
+	for _, contentType := range contentTypes {
+		kind := getResponseKind(contentType)
+		if kind == ResponseKindJson {
+			return contentType, kind
+		}
+		if selected == "" {
+			selected, selectedKind = contentType, kind
+		}
+	}
+	return selected, selectedKind
+}
+
+// getResponseKind classifies a response media type
+func getResponseKind(contentType string) ResponseKind {
+	mediaType := strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		return ResponseKindJson
+	case strings.HasPrefix(mediaType, "text/") && mediaType != "text/event-stream":
+		return ResponseKindText
+	case mediaType == "application/octet-stream",
+		strings.HasPrefix(mediaType, "audio/"),
+		strings.HasPrefix(mediaType, "image/"),
+		strings.HasPrefix(mediaType, "video/"):
+		return ResponseKindBinary
+	default:
+		return ResponseKindJson
+	}
+}
+
+// getDeprecation reads the deprecation of an operation, parameter or schema. A deprecation
+// message or replacement implies deprecated.
+func getDeprecation(deprecated bool, extensions map[string]interface{}) (Deprecation, error) {
+	deprecation := Deprecation{Deprecated: deprecated}
+	for _, ext := range []struct {
+		name  string
+		value *string
+	}{
+		{"x-coze-deprecated-message", &deprecation.DeprecatedMessage},
+		{"x-coze-deprecated-replacement", &deprecation.DeprecatedReplacement},
+	} {
+		value, ok := extensions[ext.name]
+		if !ok || value == nil {
+			continue
+		}
+		str, ok := value.(string)
+		if !ok {
+			return Deprecation{}, fmt.Errorf("%s must be a string, got %v", ext.name, value)
+		}
+		*ext.value = str
+		deprecation.Deprecated = true
+	}
+	return deprecation, nil
+}
+
+// getBodyStyle returns the body style of an operation, configuration takes precedence over x-coze-body-style
+func (p *Parser) getBodyStyle(op *openapi3.Operation) (BodyStyle, error) {
+	style := BodyStyleFlatten
+	if ext, ok := op.Extensions["x-coze-body-style"]; ok && ext != nil {
+		s, ok := ext.(string)
+		if !ok {
+			return "", fmt.Errorf("x-coze-body-style must be a string, got %v", ext)
+		}
+		style = BodyStyle(s)
+	}
+	if configured, ok := p.config.BodyStyles[op.OperationID]; ok {
+		style = configured
+	}
+
+	switch style {
+	case BodyStyleFlatten, BodyStyleModel, BodyStyleBoth:
+		return style, nil
+	default:
+		return "", fmt.Errorf("unsupported body style %q", style)
+	}
+}
+
 // topologicalSortTypes performs a deterministic topological sort of types based on their dependencies
 // starting from the given entry points (RequestBody/ResponseBody)
 func topologicalSortTypes(entryTypes []*Ty) ([]*Ty, error) {
@@ -979,6 +1562,9 @@ func (p *Parser) collectHandlerTypes(handler *HttpHandler, deps map[*Ty]bool) {
 	for _, param := range handler.QueryParams {
 		collectFromType(param.Type)
 	}
+	for _, param := range handler.CookieParams {
+		collectFromType(param.Type)
+	}
 }
 
 // convertPrimitiveType converts OpenAPI type to our primitive type
@@ -989,12 +1575,34 @@ func (p *Parser) convertPrimitiveType(typ []string, format string) PrimitiveKind
 
 	switch typ[0] {
 	case "integer":
+		switch format {
+		case "int64":
+			return PrimitiveInt64
+		case "unix-timestamp":
+			return PrimitiveUnixTimestamp
+		}
 		return PrimitiveInt
 	case "number":
+		// Decimals sent as JSON numbers would lose the precision decimal exists for, they stay floats
 		return PrimitiveFloat
 	case "string":
-		if format == "binary" {
+		switch format {
+		case "int64":
+			return PrimitiveInt64String
+		case "binary":
 			return PrimitiveBinary
+		case "date-time":
+			return PrimitiveDateTime
+		case "date":
+			return PrimitiveDate
+		case "uuid":
+			return PrimitiveUUID
+		case "uri":
+			return PrimitiveURI
+		case "byte":
+			return PrimitiveByte
+		case "decimal":
+			return PrimitiveDecimal
 		}
 		return PrimitiveString
 	case "boolean":
@@ -1004,8 +1612,15 @@ func (p *Parser) convertPrimitiveType(typ []string, format string) PrimitiveKind
 	}
 }
 
-// getRefName extracts the name from a reference
-func getRefName(ref string) string {
-	parts := strings.Split(ref, "/")
-	return parts[len(parts)-1]
+// getSchemaRefName extracts the schema name from a reference to a component schema, which may
+// live in another file, e.g. bots.yaml#/components/schemas/Bot -> Bot. It returns false for
+// references to anything else, such as #/components/schemas/Bot/properties/name.
+func getSchemaRefName(ref string) (string, bool) {
+	_, fragment, _ := strings.Cut(ref, "#")
+	name, ok := strings.CutPrefix(fragment, "/components/schemas/")
+	if !ok || name == "" || strings.Contains(name, "/") {
+		return "", false
+	}
+	// Unescape JSON pointer tokens
+	return strings.NewReplacer("~1", "/", "~0", "~").Replace(name), true
 }
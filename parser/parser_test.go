@@ -4,8 +4,10 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/stretchr/testify/require"
 )
 
@@ -36,3 +38,888 @@ func TestParser_ParseOpenAPI(t *testing.T) {
 	err = os.WriteFile(outputPath, jsonData, 0o644)
 	require.NoError(t, err)
 }
+
+const bodyStyleSpec = `
+openapi: 3.0.3
+info:
+  title: test
+  version: "1"
+paths:
+  /v1/workflow/run:
+    post:
+      operationId: RunWorkflow
+      x-coze-body-style: model
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                workflow_id:
+                  type: string
+      tags:
+        - workflows
+  /v1/chat:
+    post:
+      operationId: Chat
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                bot_id:
+                  type: string
+      tags:
+        - chat
+`
+
+func TestParser_BodyStyle(t *testing.T) {
+	parser, err := NewParser(&ModuleConfig{
+		GenerateUnnamedRequestType: func(h *HttpHandler) (string, bool) {
+			return h.Name + "Req", true
+		},
+		BodyStyles: map[string]BodyStyle{"Chat": BodyStyleBoth},
+	})
+	require.NoError(t, err)
+
+	modules, err := parser.ParseOpenAPI([]byte(bodyStyleSpec))
+	require.NoError(t, err)
+
+	run := modules["workflows"].HttpHandlers[0]
+	require.Equal(t, BodyStyleModel, run.BodyStyle)
+	require.True(t, run.RequestBody.IsNamed)
+	require.Equal(t, "RunWorkflowReq", run.RequestBody.Name)
+	require.Contains(t, modules["workflows"].Types, run.RequestBody)
+	require.Equal(t, "workflows", run.RequestBody.Module)
+
+	chat := modules["chat"].HttpHandlers[0]
+	require.Equal(t, BodyStyleBoth, chat.BodyStyle)
+	require.Equal(t, "ChatReq", chat.RequestBody.Name)
+
+	parser, err = NewParser(nil)
+	require.NoError(t, err)
+	_, err = parser.ParseOpenAPI([]byte(strings.Replace(bodyStyleSpec, "x-coze-body-style: model", "x-coze-body-style: exploded", 1)))
+	require.ErrorContains(t, err, `unsupported body style "exploded"`)
+}
+
+func TestParser_ResponseKind(t *testing.T) {
+	tests := []struct {
+		contentType string
+		want        ResponseKind
+	}{
+		{"application/json", ResponseKindJson},
+		{"application/problem+json", ResponseKindJson},
+		{"application/octet-stream", ResponseKindBinary},
+		{"audio/mpeg", ResponseKindBinary},
+		{"text/plain; charset=utf-8", ResponseKindText},
+		{"text/event-stream", ResponseKindJson},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.want, getResponseKind(tt.contentType), tt.contentType)
+	}
+
+	contentType, kind := selectResponseContentType(openapi3.Content{
+		"audio/mpeg":       openapi3.NewMediaType(),
+		"application/json": openapi3.NewMediaType(),
+	})
+	require.Equal(t, "application/json", contentType)
+	require.Equal(t, ResponseKindJson, kind)
+}
+
+const securitySpec = `
+openapi: 3.0.3
+info:
+  title: test
+  version: "1"
+security:
+  - token: []
+components:
+  securitySchemes:
+    token:
+      type: http
+      scheme: bearer
+    jwt:
+      type: oauth2
+      flows:
+        clientCredentials:
+          tokenUrl: https://api.coze.com/api/permission/oauth2/token
+          scopes:
+            app.read: Read apps
+paths:
+  /v1/bots:
+    get:
+      operationId: ListBots
+      tags:
+        - bots
+  /v1/apps:
+    get:
+      operationId: ListApps
+      security:
+        - jwt:
+            - app.read
+      tags:
+        - bots
+  /api/permission/oauth2/token:
+    post:
+      operationId: GetToken
+      security: []
+      tags:
+        - bots
+`
+
+func TestParser_Security(t *testing.T) {
+	parser, err := NewParser(&ModuleConfig{HandlerOrdering: map[string][]string{"bots": {"ListBots", "ListApps", "GetToken"}}})
+	require.NoError(t, err)
+
+	modules, err := parser.ParseOpenAPI([]byte(securitySpec))
+	require.NoError(t, err)
+
+	schemes := parser.SecuritySchemes()
+	require.Len(t, schemes, 2)
+	require.Equal(t, "jwt", schemes[0].Name)
+	require.Equal(t, OAuthFlowClientCredentials, schemes[0].Flows[0].Type)
+	require.True(t, parser.GetSecurityScheme("token").IsBearer())
+
+	handlers := modules["bots"].HttpHandlers
+	require.Equal(t, []SecurityRequirement{{Schemes: []SecurityRequirementScheme{{Name: "token", Scopes: []string{}}}}}, handlers[0].Security)
+	require.False(t, handlers[0].AllowsAnonymous())
+	require.Equal(t, []string{"app.read"}, handlers[1].Security[0].Schemes[0].Scopes)
+	require.True(t, handlers[2].AllowsAnonymous())
+}
+
+const serversSpec = `
+openapi: 3.0.3
+info:
+  title: test
+  version: "1"
+servers:
+  - url: https://api.coze.com
+  - url: https://api.coze.cn
+    x-coze-name: cn
+  - url: https://{region}.coze.com/v1/
+    variables:
+      region:
+        default: sg
+        enum:
+          - sg
+          - us
+paths:
+  /v1/apps:
+    get:
+      operationId: ListApps
+      servers:
+        - url: https://apps.coze.com
+      tags:
+        - apps
+`
+
+func TestParser_Servers(t *testing.T) {
+	parser, err := NewParser(nil)
+	require.NoError(t, err)
+
+	modules, err := parser.ParseOpenAPI([]byte(serversSpec))
+	require.NoError(t, err)
+
+	servers := parser.Servers()
+	require.Len(t, servers, 3)
+	require.Equal(t, "api_coze_com", servers[0].Name)
+	require.Equal(t, "cn", servers[1].Name)
+	require.Equal(t, "region_coze_com", servers[2].Name)
+	require.Equal(t, "https://sg.coze.com/v1", servers[2].DefaultURL())
+	require.Equal(t, []ServerURL{
+		{Name: "sg_coze_com", URL: "https://sg.coze.com/v1", Values: map[string]string{"region": "sg"}},
+		{Name: "us_coze_com", URL: "https://us.coze.com/v1", Values: map[string]string{"region": "us"}},
+	}, servers[2].URLs())
+	require.Equal(t, []ServerURL{{Name: "cn", URL: "https://api.coze.cn", Values: map[string]string{}}}, servers[1].URLs())
+
+	handler := modules["apps"].HttpHandlers[0]
+	require.Len(t, handler.Servers, 1)
+	require.Equal(t, "https://apps.coze.com", handler.Servers[0].DefaultURL())
+}
+
+func TestParser_ParamStyle(t *testing.T) {
+	explode := false
+	tests := []struct {
+		param       *openapi3.Parameter
+		wantStyle   ParamStyle
+		wantExplode bool
+	}{
+		{&openapi3.Parameter{In: "query"}, ParamStyleForm, true},
+		{&openapi3.Parameter{In: "query", Explode: &explode}, ParamStyleForm, false},
+		{&openapi3.Parameter{In: "query", Style: "deepObject"}, ParamStyleDeepObject, false},
+		{&openapi3.Parameter{In: "path"}, ParamStyleSimple, false},
+		{&openapi3.Parameter{In: "header"}, ParamStyleSimple, false},
+		{&openapi3.Parameter{In: "cookie"}, ParamStyleForm, true},
+	}
+	for _, tt := range tests {
+		style, explode := getParamStyle(tt.param)
+		require.Equal(t, tt.wantStyle, style)
+		require.Equal(t, tt.wantExplode, explode)
+	}
+}
+
+func TestParser_PathParams(t *testing.T) {
+	spec := `
+openapi: 3.0.3
+info:
+  title: test
+  version: "1"
+paths:
+  /v1/conversations/{conversation_id}/messages:
+    get:
+      operationId: ListMessages
+      tags:
+        - conversations
+`
+	parser, err := NewParser(nil)
+	require.NoError(t, err)
+	_, err = parser.ParseOpenAPI([]byte(spec))
+	require.ErrorContains(t, err, "placeholder {conversation_id} without a matching path parameter")
+
+	path := ReplacePathPlaceholders("/v1/conversations/{conversation_id}/messages/{message_id}", func(name string) string {
+		return "<" + name + ">"
+	})
+	require.Equal(t, "/v1/conversations/<conversation_id>/messages/<message_id>", path)
+}
+
+func TestParser_SharedParams(t *testing.T) {
+	spec := `
+openapi: 3.0.3
+info:
+  title: test
+  version: "1"
+paths:
+  /v1/conversations/{conversation_id}/messages:
+    parameters:
+      - name: conversation_id
+        in: path
+        required: true
+        schema:
+          type: string
+      - name: limit
+        in: query
+        schema:
+          type: integer
+    get:
+      operationId: ListMessages
+      tags:
+        - conversations
+      parameters:
+        - name: limit
+          in: query
+          required: true
+          schema:
+            type: integer
+        - name: session
+          in: cookie
+          schema:
+            type: string
+`
+	parser, err := NewParser(nil)
+	require.NoError(t, err)
+	modules, err := parser.ParseOpenAPI([]byte(spec))
+	require.NoError(t, err)
+
+	handler := modules["conversations"].HttpHandlers[0]
+	require.Len(t, handler.PathParams, 1)
+	require.Equal(t, "conversation_id", handler.PathParams[0].Name)
+	require.Len(t, handler.QueryParams, 1)
+	require.True(t, handler.QueryParams[0].Required)
+	require.Len(t, handler.CookieParams, 1)
+	require.Equal(t, "session", handler.CookieParams[0].Name)
+	require.Equal(t, ParamStyleForm, handler.CookieParams[0].Style)
+}
+
+func TestParser_Refs(t *testing.T) {
+	specPath := filepath.Join("testdata", "refs", "openapi.yaml")
+	yamlContent, err := os.ReadFile(specPath)
+	require.NoError(t, err)
+
+	parser, err := NewParser(nil)
+	require.NoError(t, err)
+	modules, err := parser.ParseOpenAPIWithPath(yamlContent, specPath)
+	require.NoError(t, err)
+
+	handler := modules["bots"].HttpHandlers[0]
+	require.Len(t, handler.PathParams, 1)
+	require.Equal(t, "bot_id", handler.PathParams[0].Name)
+
+	require.NotNil(t, handler.RequestBody)
+	require.Len(t, handler.RequestBody.Fields, 1)
+	require.Equal(t, PrimitiveString, handler.RequestBody.Fields[0].Type.PrimitiveKind)
+	require.False(t, handler.RequestBody.Fields[0].Type.IsNamed)
+
+	require.NotNil(t, handler.ResponseBody)
+	bot := parser.GetType("Bot")
+	require.NotNil(t, bot)
+	require.Equal(t, bot, handler.ResponseBody.Fields[0].Type)
+	require.NotNil(t, parser.GetType("Icon"))
+
+	name, ok := getSchemaRefName("bots.yaml#/components/schemas/Bot")
+	require.True(t, ok)
+	require.Equal(t, "Bot", name)
+	_, ok = getSchemaRefName("#/components/schemas/Bot/properties/name")
+	require.False(t, ok)
+}
+
+func TestParser_MergeSpecs(t *testing.T) {
+	botsSpec := `
+openapi: 3.0.3
+info:
+  title: bots
+  version: "1"
+servers:
+  - url: https://api.coze.com
+paths:
+  /v1/bots:
+    get:
+      operationId: ListBots
+      tags:
+        - bots
+components:
+  schemas:
+    Icon:
+      type: object
+      properties:
+        url:
+          type: string
+`
+	workflowsSpec := `
+openapi: 3.0.3
+info:
+  title: workflows
+  version: "1"
+servers:
+  - url: https://workflow.coze.com
+paths:
+  /v1/workflows:
+    get:
+      operationId: ListWorkflows
+      tags:
+        - workflows
+components:
+  schemas:
+    Icon:
+      type: object
+      properties:
+        url:
+          type: string
+`
+	parser, err := NewParser(nil)
+	require.NoError(t, err)
+	modules, err := parser.ParseOpenAPISpecs([]Spec{
+		{Path: "bots.yaml", Content: []byte(botsSpec)},
+		{Path: "workflows.yaml", Content: []byte(workflowsSpec)},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "bots.yaml", modules["bots"].HttpHandlers[0].Source)
+	require.Len(t, modules["bots"].HttpHandlers[0].Servers, 0)
+	require.Equal(t, "workflows.yaml", modules["workflows"].HttpHandlers[0].Source)
+	require.Equal(t, "https://workflow.coze.com", modules["workflows"].HttpHandlers[0].Servers[0].URL)
+	require.Len(t, parser.Servers(), 2)
+	require.NotNil(t, parser.GetType("Icon"))
+
+	// Path level servers of a path declared by another document
+	parser, err = NewParser(nil)
+	require.NoError(t, err)
+	modules, err = parser.ParseOpenAPISpecs([]Spec{
+		{Path: "bots.yaml", Content: []byte(botsSpec)},
+		{Path: "bots_upload.yaml", Content: []byte(`
+openapi: 3.0.3
+info:
+  title: bots
+  version: "1"
+paths:
+  /v1/bots:
+    servers:
+      - url: https://upload.coze.com
+    post:
+      operationId: CreateBot
+      tags:
+        - bots
+`)},
+	})
+	require.NoError(t, err)
+	handlers := make(map[string]HttpHandler)
+	for _, handler := range modules["bots"].HttpHandlers {
+		handlers[handler.Name] = handler
+	}
+	require.Len(t, handlers["ListBots"].Servers, 0)
+	require.Len(t, handlers["CreateBot"].Servers, 1)
+	require.Equal(t, "https://upload.coze.com", handlers["CreateBot"].Servers[0].URL)
+
+	// Duplicate operation ids
+	parser, err = NewParser(nil)
+	require.NoError(t, err)
+	_, err = parser.ParseOpenAPISpecs([]Spec{
+		{Path: "bots.yaml", Content: []byte(botsSpec)},
+		{Path: "bots_v2.yaml", Content: []byte(strings.ReplaceAll(botsSpec, "/v1/bots", "/v2/bots"))},
+	})
+	require.ErrorContains(t, err, "operation ListBots is declared in both bots.yaml and bots_v2.yaml")
+
+	// Same schema name with a different shape
+	parser, err = NewParser(nil)
+	require.NoError(t, err)
+	_, err = parser.ParseOpenAPISpecs([]Spec{
+		{Path: "bots.yaml", Content: []byte(botsSpec)},
+		{Path: "workflows.yaml", Content: []byte(strings.ReplaceAll(workflowsSpec, "type: string", "type: integer"))},
+	})
+	require.ErrorContains(t, err, "schema Icon of workflows.yaml conflicts")
+}
+
+func TestParser_Deprecation(t *testing.T) {
+	spec := `
+openapi: 3.0.3
+info:
+  title: test
+  version: "1"
+paths:
+  /v1/bot/get:
+    get:
+      operationId: GetBot
+      deprecated: true
+      x-coze-deprecated-replacement: RetrieveBot
+      tags:
+        - bots
+      parameters:
+        - name: space_id
+          in: query
+          x-coze-deprecated-message: space_id is ignored
+          schema:
+            type: string
+      responses:
+        "200":
+          description: ""
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Bot"
+components:
+  schemas:
+    Bot:
+      type: object
+      deprecated: true
+      properties:
+        name:
+          type: string
+          deprecated: true
+`
+	parser, err := NewParser(nil)
+	require.NoError(t, err)
+	modules, err := parser.ParseOpenAPI([]byte(spec))
+	require.NoError(t, err)
+
+	handler := modules["bots"].HttpHandlers[0]
+	require.Equal(t, Deprecation{Deprecated: true, DeprecatedReplacement: "RetrieveBot"}, handler.Deprecation)
+	require.Equal(t, Deprecation{Deprecated: true, DeprecatedMessage: "space_id is ignored"}, handler.QueryParams[0].Deprecation)
+
+	bot := parser.GetType("Bot")
+	require.True(t, bot.Deprecated)
+	require.True(t, bot.Fields[0].Deprecated)
+}
+
+func TestParser_Constraints(t *testing.T) {
+	spec := `
+openapi: 3.0.3
+info:
+  title: test
+  version: "1"
+paths:
+  /v1/bots:
+    get:
+      operationId: ListBots
+      tags:
+        - bots
+      parameters:
+        - name: page_size
+          in: query
+          schema:
+            type: integer
+            minimum: 1
+            maximum: 100
+      responses:
+        "200":
+          description: ""
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Bot"
+components:
+  schemas:
+    Bot:
+      type: object
+      properties:
+        name:
+          type: string
+          minLength: 1
+          maxLength: 20
+          pattern: "^[a-z]+$"
+        score:
+          type: number
+          minimum: 0
+          exclusiveMinimum: true
+          multipleOf: 0.5
+        tags:
+          type: array
+          maxItems: 3
+          items:
+            type: string
+        description:
+          type: string
+`
+	parser, err := NewParser(nil)
+	require.NoError(t, err)
+	modules, err := parser.ParseOpenAPI([]byte(spec))
+	require.NoError(t, err)
+
+	pageSize := modules["bots"].HttpHandlers[0].QueryParams[0]
+	require.Equal(t, 1.0, *pageSize.Constraints.Minimum)
+	require.Equal(t, 100.0, *pageSize.Constraints.Maximum)
+
+	fields := make(map[string]TyField)
+	for _, field := range parser.GetType("Bot").Fields {
+		fields[field.Name] = field
+	}
+	require.Equal(t, uint64(1), *fields["name"].Constraints.MinLength)
+	require.Equal(t, uint64(20), *fields["name"].Constraints.MaxLength)
+	require.Equal(t, "^[a-z]+$", fields["name"].Constraints.Pattern)
+	require.True(t, fields["score"].Constraints.ExclusiveMinimum)
+	require.Equal(t, 0.5, *fields["score"].Constraints.MultipleOf)
+	require.Equal(t, uint64(3), *fields["tags"].Constraints.MaxItems)
+	require.Nil(t, fields["tags"].Constraints.MinItems)
+	require.Nil(t, fields["description"].Constraints)
+}
+
+func TestParser_Formats(t *testing.T) {
+	spec := `
+openapi: 3.0.3
+info:
+  title: test
+  version: "1"
+paths:
+  /v1/events:
+    get:
+      operationId: GetEvent
+      tags:
+        - events
+      responses:
+        "200":
+          description: ""
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Event"
+components:
+  schemas:
+    Event:
+      type: object
+      properties:
+        id:
+          type: integer
+          format: int64
+        bot_id:
+          type: string
+          format: int64
+        created_at:
+          type: integer
+          x-coze-format: unix-timestamp
+        updated_at:
+          type: string
+          format: date-time
+        day:
+          type: string
+          format: date
+        uuid:
+          type: string
+          format: uuid
+        link:
+          type: string
+          format: uri
+        payload:
+          type: string
+          format: byte
+        amount:
+          type: string
+          format: decimal
+        score:
+          type: number
+          format: decimal
+`
+	parser, err := NewParser(nil)
+	require.NoError(t, err)
+	_, err = parser.ParseOpenAPI([]byte(spec))
+	require.NoError(t, err)
+
+	kinds := make(map[string]PrimitiveKind)
+	for _, field := range parser.GetType("Event").Fields {
+		kinds[field.Name] = field.Type.PrimitiveKind
+	}
+	require.Equal(t, map[string]PrimitiveKind{
+		"id":         PrimitiveInt64,
+		"bot_id":     PrimitiveInt64String,
+		"created_at": PrimitiveUnixTimestamp,
+		"updated_at": PrimitiveDateTime,
+		"day":        PrimitiveDate,
+		"uuid":       PrimitiveUUID,
+		"link":       PrimitiveURI,
+		"payload":    PrimitiveByte,
+		"amount":     PrimitiveDecimal,
+		"score":      PrimitiveFloat,
+	}, kinds)
+}
+
+func TestParser_Defaults(t *testing.T) {
+	spec := `
+openapi: 3.0.3
+info:
+  title: test
+  version: "1"
+paths:
+  /v1/bots:
+    get:
+      operationId: ListBots
+      tags:
+        - bots
+      parameters:
+        - name: page_size
+          in: query
+          schema:
+            type: integer
+            default: 50
+      responses:
+        "200":
+          description: ""
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Bot"
+components:
+  schemas:
+    Bot:
+      type: object
+      properties:
+        name:
+          type: string
+          default: bot
+        tags:
+          type: array
+          items:
+            type: string
+          default: [a, b]
+        description:
+          type: string
+`
+	parser, err := NewParser(nil)
+	require.NoError(t, err)
+	modules, err := parser.ParseOpenAPI([]byte(spec))
+	require.NoError(t, err)
+
+	require.Equal(t, 50.0, modules["bots"].HttpHandlers[0].QueryParams[0].DefaultValue)
+
+	defaults := make(map[string]any)
+	for _, field := range parser.GetType("Bot").Fields {
+		defaults[field.Name] = field.DefaultValue
+	}
+	require.Equal(t, map[string]any{"name": "bot", "tags": []any{"a", "b"}, "description": nil}, defaults)
+}
+
+func TestParser_ReadWriteOnly(t *testing.T) {
+	spec := `
+openapi: 3.0.3
+info:
+  title: test
+  version: "1"
+paths:
+  /v1/bots:
+    get:
+      operationId: GetBot
+      tags:
+        - bots
+      responses:
+        "200":
+          description: ""
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Bot"
+components:
+  schemas:
+    Bot:
+      type: object
+      properties:
+        id:
+          type: string
+          readOnly: true
+        secret:
+          type: string
+          writeOnly: true
+        name:
+          type: string
+`
+	parser, err := NewParser(nil)
+	require.NoError(t, err)
+	_, err = parser.ParseOpenAPI([]byte(spec))
+	require.NoError(t, err)
+
+	fields := make(map[string]TyField)
+	for _, field := range parser.GetType("Bot").Fields {
+		fields[field.Name] = field
+	}
+	require.True(t, fields["id"].ReadOnly)
+	require.False(t, fields["id"].WriteOnly)
+	require.True(t, fields["secret"].WriteOnly)
+	require.False(t, fields["name"].ReadOnly || fields["name"].WriteOnly)
+}
+
+func TestParser_Examples(t *testing.T) {
+	spec := `
+openapi: 3.0.3
+info:
+  title: test
+  version: "1"
+paths:
+  /v1/bots:
+    post:
+      operationId: CreateBot
+      tags:
+        - bots
+      parameters:
+        - name: space_id
+          in: query
+          example: "123"
+          schema:
+            type: string
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                name:
+                  type: string
+                  example: helper
+            example:
+              name: helper
+      responses:
+        "200":
+          description: ""
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Bot"
+              examples:
+                published:
+                  summary: A published bot
+                  value:
+                    bot_id: "1"
+components:
+  schemas:
+    Bot:
+      type: object
+      example:
+        bot_id: "2"
+      properties:
+        bot_id:
+          type: string
+`
+	parser, err := NewParser(nil)
+	require.NoError(t, err)
+	modules, err := parser.ParseOpenAPI([]byte(spec))
+	require.NoError(t, err)
+
+	handler := modules["bots"].HttpHandlers[0]
+	require.Equal(t, []Example{{Value: "123"}}, handler.QueryParams[0].Examples)
+	require.Equal(t, []Example{{Value: map[string]any{"name": "helper"}}}, handler.RequestExamples)
+	require.Equal(t, []Example{{Value: "helper"}}, handler.RequestBody.Fields[0].Examples)
+	require.Equal(t, []Example{{Name: "published", Summary: "A published bot", Value: map[string]any{"bot_id": "1"}}}, handler.ResponseExamples)
+	require.Equal(t, []Example{{Value: map[string]any{"bot_id": "2"}}}, parser.GetType("Bot").Examples)
+}
+
+func TestParser_Descriptions(t *testing.T) {
+	spec := `
+openapi: 3.0.3
+info:
+  title: test
+  version: "1"
+paths:
+  /v1/bots:
+    get:
+      operationId: GetBot
+      summary: Get a bot
+      description: 获取智能体
+      tags:
+        - bots
+      parameters:
+        - name: bot_id
+          in: query
+          description: 智能体 ID
+          x-coze-i18n:
+            en: The ID of the bot
+          schema:
+            type: string
+      responses:
+        "200":
+          description: ""
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Bot"
+  /v1/bots/create:
+    post:
+      operationId: CreateBot
+      summary: Create bot
+      description: Create a new bot in the space.
+      tags:
+        - bots
+      responses:
+        "200":
+          description: ""
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Bot"
+components:
+  schemas:
+    Bot:
+      type: object
+      description: The bot
+      properties:
+        name:
+          title: The name of the bot
+          description: 智能体名称
+          type: string
+        icon_url:
+          description: 头像
+          x-coze-i18n:
+            zh: 智能体头像
+          type: string
+`
+	parser, err := NewParser(nil)
+	require.NoError(t, err)
+	modules, err := parser.ParseOpenAPI([]byte(spec))
+	require.NoError(t, err)
+
+	handlers := make(map[string]HttpHandler)
+	for _, handler := range modules["bots"].HttpHandlers {
+		handlers[handler.Name] = handler
+	}
+	handler := handlers["GetBot"]
+	require.Equal(t, "获取智能体", handler.Description)
+	require.Equal(t, Descriptions{LangEn: "Get a bot", LangZh: "获取智能体"}, handler.Descriptions)
+	require.Equal(t, Descriptions{LangEn: "Create a new bot in the space."}, handlers["CreateBot"].Descriptions)
+	require.Equal(t, Descriptions{LangEn: "The ID of the bot", LangZh: "智能体 ID"}, handler.QueryParams[0].Descriptions)
+
+	bot := parser.GetType("Bot")
+	require.Equal(t, Descriptions{LangEn: "The bot"}, bot.Descriptions)
+	fields := make(map[string]TyField)
+	for _, field := range bot.Fields {
+		fields[field.Name] = field
+	}
+	require.Equal(t, "The name of the bot", fields["name"].Description)
+	require.Equal(t, Descriptions{LangEn: "The name of the bot", LangZh: "智能体名称"}, fields["name"].Descriptions)
+	require.Equal(t, Descriptions{LangZh: "智能体头像"}, fields["icon_url"].Descriptions)
+
+	_, err = getDescriptions(nil, map[string]interface{}{"x-coze-i18n": "text"})
+	require.Error(t, err)
+}
@@ -0,0 +1,207 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// SecuritySchemeType represents the kind of a security scheme
+type SecuritySchemeType string
+
+const (
+	SecuritySchemeHttp          SecuritySchemeType = "http"
+	SecuritySchemeApiKey        SecuritySchemeType = "apiKey"
+	SecuritySchemeOAuth2        SecuritySchemeType = "oauth2"
+	SecuritySchemeOpenIdConnect SecuritySchemeType = "openIdConnect"
+)
+
+// SecurityScheme represents an authentication method declared in components.securitySchemes
+type SecurityScheme struct {
+	Name        string             `json:"name"`
+	Type        SecuritySchemeType `json:"type"`
+	Description string             `json:"description,omitempty"`
+
+	// For http schemes
+	Scheme       string `json:"scheme,omitempty"` // e.g. bearer or basic
+	BearerFormat string `json:"bearer_format,omitempty"`
+
+	// For apiKey schemes
+	In        string `json:"in,omitempty"`         // header, query or cookie
+	ParamName string `json:"param_name,omitempty"` // name of the header, query or cookie parameter
+
+	// For oauth2 and openIdConnect schemes
+	Flows            []OAuthFlow `json:"flows,omitempty"`
+	OpenIdConnectURL string      `json:"open_id_connect_url,omitempty"`
+}
+
+// IsBearer checks if the scheme sends its credential as an Authorization bearer token
+func (s *SecurityScheme) IsBearer() bool {
+	switch s.Type {
+	case SecuritySchemeOAuth2, SecuritySchemeOpenIdConnect:
+		return true
+	case SecuritySchemeHttp:
+		return s.Scheme == "" || s.Scheme == "bearer" || s.Scheme == "Bearer"
+	default:
+		return false
+	}
+}
+
+// OAuthFlowType represents the OAuth2 grant of a flow
+type OAuthFlowType string
+
+const (
+	OAuthFlowImplicit          OAuthFlowType = "implicit"
+	OAuthFlowPassword          OAuthFlowType = "password"
+	OAuthFlowClientCredentials OAuthFlowType = "client_credentials"
+	OAuthFlowAuthorizationCode OAuthFlowType = "authorization_code"
+)
+
+// OAuthFlow represents a single OAuth2 flow of a security scheme
+type OAuthFlow struct {
+	Type             OAuthFlowType     `json:"type"`
+	AuthorizationURL string            `json:"authorization_url,omitempty"`
+	TokenURL         string            `json:"token_url,omitempty"`
+	RefreshURL       string            `json:"refresh_url,omitempty"`
+	Scopes           map[string]string `json:"scopes,omitempty"`
+}
+
+// SecurityRequirement lists the schemes that must all be satisfied to call an operation.
+// An empty requirement means the operation can be called anonymously.
+type SecurityRequirement struct {
+	Schemes []SecurityRequirementScheme `json:"schemes,omitempty"`
+}
+
+// SecurityRequirementScheme is a scheme referenced by a requirement with the scopes it needs
+type SecurityRequirementScheme struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes,omitempty"`
+}
+
+// AllowsAnonymous checks if the handler can be called without credentials,
+// that is when it has no requirements at all or one of them is empty.
+func (h *HttpHandler) AllowsAnonymous() bool {
+	if len(h.Security) == 0 {
+		return true
+	}
+	for _, requirement := range h.Security {
+		if len(requirement.Schemes) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// SecuritySchemes returns all security schemes of the document, sorted by name
+func (p *Parser) SecuritySchemes() []*SecurityScheme {
+	schemes := make([]*SecurityScheme, 0, len(p.securitySchemes))
+	for _, scheme := range p.securitySchemes {
+		schemes = append(schemes, scheme)
+	}
+	sort.Slice(schemes, func(i, j int) bool {
+		return schemes[i].Name < schemes[j].Name
+	})
+	return schemes
+}
+
+// GetSecurityScheme returns a security scheme by name
+func (p *Parser) GetSecurityScheme(name string) *SecurityScheme {
+	return p.securitySchemes[name]
+}
+
+// processSecuritySchemes processes all security schemes from components
+func (p *Parser) processSecuritySchemes() error {
+	if p.doc.Components == nil {
+		return nil
+	}
+
+	for name, ref := range p.doc.Components.SecuritySchemes {
+		if ref == nil || ref.Value == nil {
+			return fmt.Errorf("security scheme %s has no value", name)
+		}
+		scheme, err := convertSecurityScheme(name, ref.Value)
+		if err != nil {
+			return fmt.Errorf("failed to convert security scheme %s: %w", name, err)
+		}
+		p.securitySchemes[name] = scheme
+	}
+	return nil
+}
+
+// convertSecurityScheme converts an OpenAPI security scheme to our type system
+func convertSecurityScheme(name string, s *openapi3.SecurityScheme) (*SecurityScheme, error) {
+	scheme := &SecurityScheme{
+		Name:        name,
+		Type:        SecuritySchemeType(s.Type),
+		Description: s.Description,
+	}
+
+	switch scheme.Type {
+	case SecuritySchemeHttp:
+		scheme.Scheme = s.Scheme
+		scheme.BearerFormat = s.BearerFormat
+	case SecuritySchemeApiKey:
+		scheme.In = s.In
+		scheme.ParamName = s.Name
+	case SecuritySchemeOAuth2:
+		if s.Flows != nil {
+			for _, flow := range []struct {
+				typ  OAuthFlowType
+				flow *openapi3.OAuthFlow
+			}{
+				{OAuthFlowImplicit, s.Flows.Implicit},
+				{OAuthFlowPassword, s.Flows.Password},
+				{OAuthFlowClientCredentials, s.Flows.ClientCredentials},
+				{OAuthFlowAuthorizationCode, s.Flows.AuthorizationCode},
+			} {
+				if flow.flow == nil {
+					continue
+				}
+				scheme.Flows = append(scheme.Flows, OAuthFlow{
+					Type:             flow.typ,
+					AuthorizationURL: flow.flow.AuthorizationURL,
+					TokenURL:         flow.flow.TokenURL,
+					RefreshURL:       flow.flow.RefreshURL,
+					Scopes:           flow.flow.Scopes,
+				})
+			}
+		}
+	case SecuritySchemeOpenIdConnect:
+		scheme.OpenIdConnectURL = s.OpenIdConnectUrl
+	default:
+		return nil, fmt.Errorf("unsupported security scheme type %q", s.Type)
+	}
+	return scheme, nil
+}
+
+// convertSecurity resolves the security requirements of an operation, falling back to the
+// document level requirements when the operation does not declare its own
+func (p *Parser) convertSecurity(op *openapi3.Operation) ([]SecurityRequirement, error) {
+	requirements := p.doc.Security
+	if op.Security != nil {
+		requirements = *op.Security
+	}
+
+	result := make([]SecurityRequirement, 0, len(requirements))
+	for _, requirement := range requirements {
+		names := make([]string, 0, len(requirement))
+		for name := range requirement {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		converted := SecurityRequirement{}
+		for _, name := range names {
+			if p.securitySchemes[name] == nil {
+				return nil, fmt.Errorf("security scheme %s not found", name)
+			}
+			converted.Schemes = append(converted.Schemes, SecurityRequirementScheme{
+				Name:   name,
+				Scopes: requirement[name],
+			})
+		}
+		result = append(result, converted)
+	}
+	return result, nil
+}
@@ -0,0 +1,186 @@
+package parser
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"golang.org/x/exp/slices"
+)
+
+// Server represents a base URL declared in a servers block
+type Server struct {
+	Name        string           `json:"name"` // from x-coze-name, or derived from the host
+	URL         string           `json:"url"`
+	Description string           `json:"description,omitempty"`
+	Variables   []ServerVariable `json:"variables,omitempty"`
+}
+
+// ServerVariable represents a substitutable part of a server URL
+type ServerVariable struct {
+	Name        string   `json:"name"`
+	Default     string   `json:"default"`
+	Description string   `json:"description,omitempty"`
+	Enum        []string `json:"enum,omitempty"`
+}
+
+// DefaultURL returns the server URL with every variable replaced by its default value
+func (s *Server) DefaultURL() string {
+	return s.Resolve(nil)
+}
+
+// ServerURL is a server URL with its variables substituted
+type ServerURL struct {
+	Name   string            `json:"name"`
+	URL    string            `json:"url"`
+	Values map[string]string `json:"values,omitempty"` // the value of each variable
+}
+
+// URLs returns the server URL for every combination of the enum values of its variables,
+// starting with the defaults, e.g. https://api.coze.{region} with region com or cn gives
+// https://api.coze.com and https://api.coze.cn. Variables without enum only take their default.
+// The name of each URL substitutes the variables in the server name, or is suffixed with the
+// values of the variables the name does not mention.
+func (s *Server) URLs() []ServerURL {
+	combinations := []map[string]string{{}}
+	for _, variable := range s.Variables {
+		values := []string{variable.Default}
+		for _, value := range variable.Enum {
+			if value != variable.Default {
+				values = append(values, value)
+			}
+		}
+
+		var next []map[string]string
+		for _, combination := range combinations {
+			for _, value := range values {
+				extended := map[string]string{variable.Name: value}
+				for name, v := range combination {
+					extended[name] = v
+				}
+				next = append(next, extended)
+			}
+		}
+		combinations = next
+	}
+
+	urls := make([]ServerURL, 0, len(combinations))
+	for _, values := range combinations {
+		serverURL := ServerURL{Name: s.Name, URL: s.Resolve(values), Values: values}
+		if len(combinations) > 1 {
+			words := strings.Split(s.Name, "_")
+			for _, variable := range s.Variables {
+				if len(variable.Enum) < 2 {
+					continue
+				}
+				value := getServerName(values[variable.Name])
+				if i := slices.Index(words, strings.ToLower(variable.Name)); i >= 0 {
+					words[i] = value
+				} else {
+					words = append(words, value)
+				}
+			}
+			serverURL.Name = strings.Join(words, "_")
+		}
+		urls = append(urls, serverURL)
+	}
+	return urls
+}
+
+// Resolve returns the server URL with the variables replaced by values, variables without a
+// value take their default
+func (s *Server) Resolve(values map[string]string) string {
+	result := s.URL
+	for _, variable := range s.Variables {
+		value, ok := values[variable.Name]
+		if !ok {
+			value = variable.Default
+		}
+		result = strings.ReplaceAll(result, "{"+variable.Name+"}", value)
+	}
+	return strings.TrimSuffix(result, "/")
+}
+
+// Servers returns the document level servers in declaration order
+func (p *Parser) Servers() []Server {
+	return p.servers
+}
+
+// processServers processes the document level servers
+func (p *Parser) processServers() error {
+	servers, err := convertServers(p.doc.Servers)
+	if err != nil {
+		return err
+	}
+	p.servers = servers
+	return nil
+}
+
+// convertServers converts OpenAPI servers to our type system, making their names unique
+func convertServers(servers openapi3.Servers) ([]Server, error) {
+	result := make([]Server, 0, len(servers))
+	names := make(map[string]int)
+	for _, s := range servers {
+		if s == nil {
+			continue
+		}
+
+		server := Server{
+			URL:         s.URL,
+			Description: s.Description,
+		}
+
+		if ext, ok := s.Extensions["x-coze-name"]; ok && ext != nil {
+			name, ok := ext.(string)
+			if !ok {
+				return nil, fmt.Errorf("x-coze-name of server %s must be a string, got %v", s.URL, ext)
+			}
+			server.Name = name
+		} else {
+			server.Name = getServerName(s.URL)
+		}
+
+		variableNames := make([]string, 0, len(s.Variables))
+		for name := range s.Variables {
+			variableNames = append(variableNames, name)
+		}
+		sort.Strings(variableNames)
+		for _, name := range variableNames {
+			variable := s.Variables[name]
+			server.Variables = append(server.Variables, ServerVariable{
+				Name:        name,
+				Default:     variable.Default,
+				Description: variable.Description,
+				Enum:        variable.Enum,
+			})
+		}
+
+		// Make names unique by appending the occurrence count
+		names[server.Name]++
+		if count := names[server.Name]; count > 1 {
+			server.Name = fmt.Sprintf("%s_%d", server.Name, count)
+		}
+
+		result = append(result, server)
+	}
+	return result, nil
+}
+
+// getServerName derives a server name from the host of its URL, e.g. api.coze.com -> api_coze_com
+func getServerName(serverURL string) string {
+	// Variables are kept by name, https://{region}.coze.com -> region_coze_com
+	serverURL = strings.NewReplacer("{", "", "}", "").Replace(serverURL)
+	name := serverURL
+	if u, err := url.Parse(serverURL); err == nil && u.Host != "" {
+		name = u.Host
+	}
+	name = regexp.MustCompile(`[^a-zA-Z0-9]+`).ReplaceAllString(name, "_")
+	name = strings.Trim(name, "_")
+	if name == "" {
+		return "default"
+	}
+	return strings.ToLower(name)
+}
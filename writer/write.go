@@ -6,7 +6,6 @@ import (
 	"log"
 	"os"
 	"path/filepath"
-	"strings"
 )
 
 func WriteOutput(ctx context.Context, files map[string]string, outputPath string) error {
@@ -16,21 +15,19 @@ func WriteOutput(ctx context.Context, files map[string]string, outputPath string
 		return fmt.Errorf("failed to create output directory: %v", err)
 	}
 
-	// Write each generated file
-	for dir, content := range files {
-		// Convert module name (with dots) to directory path
-		dirPath := strings.ReplaceAll(dir, ".", string(os.PathSeparator))
-		outputFilePath := filepath.Join(outputPath, dirPath, "__init__.py")
+	// Write each generated file, keys are slash separated paths relative to the output directory
+	for file, content := range files {
+		outputFilePath := filepath.Join(outputPath, filepath.FromSlash(file))
 
 		// Create subdirectory if needed
 		err = os.MkdirAll(filepath.Dir(outputFilePath), 0o755)
 		if err != nil {
-			return fmt.Errorf("failed to create directory for %s: %v", dir, err)
+			return fmt.Errorf("failed to create directory for %s: %v", file, err)
 		}
 
 		err = os.WriteFile(outputFilePath, []byte(content), 0o644)
 		if err != nil {
-			return fmt.Errorf("failed to write file %s: %v", dir, err)
+			return fmt.Errorf("failed to write file %s: %v", file, err)
 		}
 		log.Printf("Successfully generated Python file at: %s", outputFilePath)
 	}